@@ -0,0 +1,99 @@
+package locale
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"io"
+	"strings"
+	"sync"
+)
+
+// LocaleAliasPath is the path of glibc's locale alias file.
+//
+// The embedded copy will be used if this file is not readable.
+var LocaleAliasPath = "/usr/share/locale/locale.alias"
+
+//go:embed locale.alias
+var embeddedLocaleAlias []byte
+
+// embeddedAliases is the parsed embedded copy of locale alias file.
+var embeddedAliases = sync.OnceValue(func() map[string]string {
+	return parseAliases(bytes.NewReader(embeddedLocaleAlias))
+})
+
+// aliasCache caches the parsed locale alias files of the OS file system by
+// path, so that they are only read once.
+var aliasCache struct {
+	sync.Mutex
+	m map[string]map[string]string
+}
+
+// resolveAlias will resolve locale alias like "german" into the real
+// locale name like "de_DE.ISO-8859-1" as glibc does.
+//
+// s will be returned as is if it's not an alias.
+//
+// ref: https://www.gnu.org/software/libc/manual/html_node/Locale-Names.html
+func (c *Config) resolveAlias(s string) string {
+	if v, ok := c.aliases()[strings.ToLower(s)]; ok {
+		return v
+	}
+	return s
+}
+
+// aliases returns the parsed locale alias file at LocaleAliasPath, the
+// embedded copy will be used if it's not readable.
+//
+// Alias files in the OS file system are cached by path, while files in
+// c.FS are read every time.
+func (c *Config) aliases() map[string]string {
+	fp := LocaleAliasPath
+	if c.FS == nil {
+		aliasCache.Lock()
+		defer aliasCache.Unlock()
+		if m, ok := aliasCache.m[fp]; ok {
+			return m
+		}
+	}
+
+	m := embeddedAliases()
+	if content, err := c.readFile(fp); err == nil {
+		m = parseAliases(bytes.NewReader(content))
+	}
+
+	if c.FS == nil {
+		if aliasCache.m == nil {
+			aliasCache.m = make(map[string]map[string]string)
+		}
+		aliasCache.m[fp] = m
+	}
+	return m
+}
+
+// parseAliases will parse the locale alias file into a map keyed by lower
+// cased alias names.
+//
+// Content should be like:
+//
+//	# comment
+//	german		de_DE.ISO-8859-1
+//	japanese.euc	ja_JP.eucJP
+//
+// All entries are case independent, the first one wins if an alias is
+// defined more than once.
+func parseAliases(r io.Reader) map[string]string {
+	m := make(map[string]string)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		if _, ok := m[name]; !ok {
+			m[name] = fields[1]
+		}
+	}
+	return m
+}
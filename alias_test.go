@@ -0,0 +1,120 @@
+package locale
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestResolveAlias(t *testing.T) {
+	tmpDir := t.TempDir()
+	fp := path.Join(tmpDir, "locale.alias")
+	err := os.WriteFile(fp, []byte("# comment\nklingon\ttlh_US.UTF-8\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		aliasPath string
+		input     string
+		want      string
+	}{
+		{"embedded", "/not/exist/locale.alias", "german", "de_DE.ISO-8859-1"},
+		{"case independent", "/not/exist/locale.alias", "German", "de_DE.ISO-8859-1"},
+		{"with codeset", "/not/exist/locale.alias", "japanese.euc", "ja_JP.eucJP"},
+		{"not alias", "/not/exist/locale.alias", "en_US.UTF-8", "en_US.UTF-8"},
+		{"custom file", fp, "klingon", "tlh_US.UTF-8"},
+		{"custom file without embedded", fp, "german", "german"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := LocaleAliasPath
+			defer func() { LocaleAliasPath = origin }()
+			LocaleAliasPath = tt.aliasPath
+
+			if got := defaultConfig.resolveAlias(tt.input); got != tt.want {
+				t.Errorf("resolveAlias() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectViaEnvAlias(t *testing.T) {
+	setupEnv()
+	defer setupEnv()
+
+	origin := LocaleAliasPath
+	defer func() { LocaleAliasPath = origin }()
+	LocaleAliasPath = "/not/exist/locale.alias"
+
+	err := os.Setenv("LANG", "german")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := detectViaEnvLc(defaultConfig)
+	if err != nil {
+		t.Errorf("detectViaEnvLc(defaultConfig) error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"de_DE"}) {
+		t.Errorf("detectViaEnvLc(defaultConfig) = %v, want %v", got, []string{"de_DE"})
+	}
+
+	err = os.Setenv("LANGUAGE", "french:japanese.euc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err = detectViaEnvLanguage(defaultConfig)
+	if err != nil {
+		t.Errorf("detectViaEnvLanguage(defaultConfig) error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"fr_FR", "ja_JP"}) {
+		t.Errorf("detectViaEnvLanguage(defaultConfig) = %v, want %v", got, []string{"fr_FR", "ja_JP"})
+	}
+}
+
+func TestAliasesCache(t *testing.T) {
+	fp := path.Join(t.TempDir(), "locale.alias")
+	err := os.WriteFile(fp, []byte("klingon\ttlh_US.UTF-8\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	origin := LocaleAliasPath
+	defer func() { LocaleAliasPath = origin }()
+	LocaleAliasPath = fp
+
+	if got := defaultConfig.resolveAlias("klingon"); got != "tlh_US.UTF-8" {
+		t.Errorf("resolveAlias() = %v, want %v", got, "tlh_US.UTF-8")
+	}
+	// Alias file in the OS file system is only read once.
+	if err = os.Remove(fp); err != nil {
+		t.Fatal(err)
+	}
+	if got := defaultConfig.resolveAlias("klingon"); got != "tlh_US.UTF-8" {
+		t.Errorf("resolveAlias() = %v, want %v", got, "tlh_US.UTF-8")
+	}
+
+	// Alias file in Config.FS is read every time.
+	files := fstest.MapFS{fp[1:]: {Data: []byte("klingon\ttlh_US.UTF-8\n")}}
+	c := &Config{FS: files}
+	if got := c.resolveAlias("klingon"); got != "tlh_US.UTF-8" {
+		t.Errorf("resolveAlias() = %v, want %v", got, "tlh_US.UTF-8")
+	}
+	delete(files, fp[1:])
+	if got := c.resolveAlias("klingon"); got != "klingon" {
+		t.Errorf("resolveAlias() = %v, want %v", got, "klingon")
+	}
+}
+
+func TestParseAliases(t *testing.T) {
+	content := "# comment\nGerman\tde_DE.ISO-8859-1\ngerman\tde_AT.ISO-8859-1\ninvalid\n"
+	expect := map[string]string{"german": "de_DE.ISO-8859-1"}
+	if got := parseAliases(strings.NewReader(content)); !reflect.DeepEqual(got, expect) {
+		t.Errorf("parseAliases() = %v, want %v", got, expect)
+	}
+}
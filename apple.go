@@ -0,0 +1,182 @@
+package locale
+
+import (
+	"errors"
+	"path"
+	"sort"
+	"strings"
+)
+
+// AppleGlobalPreferencesPath is the path of system level global preferences
+// on macOS, which is the "/Library/Preferences/.GlobalPreferences" domain of
+// User Defaults System.
+var AppleGlobalPreferencesPath = "/Library/Preferences/.GlobalPreferences.plist"
+
+// getAppleGlobalPreferencesPaths returns the global preferences in the order
+// of priority:
+//   - "$HOME/Library/Preferences/.GlobalPreferences.plist" (the "-g" domain)
+//   - "/Library/Preferences/.GlobalPreferences.plist"
+func getAppleGlobalPreferencesPaths(c *Config) []string {
+	var paths []string
+	if home, ok := c.lookupEnv("HOME"); ok && home != "" {
+		paths = append(paths, path.Join(home, "Library", "Preferences", ".GlobalPreferences.plist"))
+	}
+	return append(paths, AppleGlobalPreferencesPath)
+}
+
+// getAppleBundlePreferencesPaths returns the preferences of application
+// domain bundleID in the order of priority, nil will be returned if bundleID
+// is not valid:
+//   - "$HOME/Library/Containers/<bundleID>/Data/Library/Preferences/<bundleID>.plist" (sandboxed)
+//   - "$HOME/Library/Preferences/<bundleID>.plist"
+//   - "/Library/Preferences/<bundleID>.plist"
+func getAppleBundlePreferencesPaths(c *Config, bundleID string) []string {
+	// bundleID must be a file name in preferences dirs.
+	if bundleID == "" || bundleID == "." || bundleID == ".." || strings.Contains(bundleID, "/") {
+		return nil
+	}
+
+	name := bundleID + ".plist"
+	var paths []string
+	if home, ok := c.lookupEnv("HOME"); ok && home != "" {
+		paths = append(paths,
+			path.Join(home, "Library", "Containers", bundleID, "Data", "Library", "Preferences", name),
+			path.Join(home, "Library", "Preferences", name),
+		)
+	}
+	return append(paths, path.Join("/Library", "Preferences", name))
+}
+
+// readApplePreferences will read the preferences plist fp into a map.
+func readApplePreferences(c *Config, fp string) (map[string]any, error) {
+	content, err := c.readFile(fp)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := decodePlist(content)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, errors.New("preferences is not a dict")
+	}
+	return m, nil
+}
+
+// appleLocale returns the raw "AppleLocale" in prefs like
+// "en_US@rg=gbzzzz", Canonicalize converts it into BCP 47.
+func appleLocale(prefs map[string]any) (string, bool) {
+	s, ok := prefs["AppleLocale"].(string)
+	if !ok || s == "" {
+		return "", false
+	}
+	return s, true
+}
+
+// icuKeywordToBCP47 maps ICU locale keywords to BCP 47 unicode extension
+// keys, keys in BCP 47 form are accepted as well and other keywords are
+// dropped.
+//
+// ref: https://unicode-org.github.io/icu/userguide/locale/#keywords
+var icuKeywordToBCP47 = map[string]string{
+	"calendar":  "ca",
+	"collation": "co",
+	"currency":  "cu",
+	"hours":     "hc",
+	"measure":   "ms",
+	"numbers":   "nu",
+
+	"ca": "ca",
+	"co": "co",
+	"cu": "cu",
+	"fw": "fw",
+	"hc": "hc",
+	"ms": "ms",
+	"nu": "nu",
+	"rg": "rg",
+}
+
+// icuValueToBCP47 maps legacy ICU keyword values which differ from their
+// BCP 47 types.
+//
+// ref: https://github.com/unicode-org/cldr/tree/main/common/bcp47
+var icuValueToBCP47 = map[string]map[string]string{
+	"ca": {
+		"ethiopic-amete-alem": "ethioaa",
+		"gregorian":           "gregory",
+	},
+	"co": {
+		"dictionary":  "dict",
+		"phonebook":   "phonebk",
+		"traditional": "trad",
+		"gb2312han":   "gb2312",
+	},
+	"ms": {
+		"imperial": "uksystem",
+	},
+}
+
+// parseICUKeywords will convert ICU keywords like
+// "rg=gbzzzz;calendar=japanese" into sorted BCP 47 unicode extension
+// keywords like ["ca-japanese", "rg-gbzzzz"].
+//
+// Invalid keywords are ignored.
+//
+// ref: https://unicode-org.github.io/icu/userguide/locale/#keywords
+func parseICUKeywords(keywords string) []string {
+	var exts []string
+	seen := make(map[string]bool)
+	for _, kv := range strings.Split(keywords, ";") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		k = strings.ToLower(strings.TrimSpace(k))
+		v = strings.ToLower(strings.TrimSpace(v))
+		// The first one wins if a key is set more than once.
+		key, ok := icuKeywordToBCP47[k]
+		if !ok || seen[key] {
+			continue
+		}
+		if bv, ok := icuValueToBCP47[key][v]; ok {
+			v = bv
+		}
+		if isUnicodeExtensionType(v) {
+			seen[key] = true
+			exts = append(exts, key+"-"+v)
+		}
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// isUnicodeExtensionType reports whether s is a valid unicode extension
+// type which is one or more "-" separated 3 to 8 alphanumerics.
+func isUnicodeExtensionType(s string) bool {
+	for _, v := range strings.Split(s, "-") {
+		if len(v) < 3 || len(v) > 8 {
+			return false
+		}
+		for i := 0; i < len(v); i++ {
+			if !('a' <= v[i] && v[i] <= 'z' || '0' <= v[i] && v[i] <= '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// appleLanguages returns the "AppleLanguages" in prefs like ["en-US", "ja"].
+func appleLanguages(prefs map[string]any) ([]string, bool) {
+	arr, _ := prefs["AppleLanguages"].([]any)
+
+	var langs []string
+	for _, v := range arr {
+		if s, ok := v.(string); ok && s != "" {
+			langs = append(langs, s)
+		}
+	}
+	return langs, len(langs) > 0
+}
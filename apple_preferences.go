@@ -0,0 +1,175 @@
+package locale
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// Preferences is the regional format preferences of macOS in the global
+// domain of User Defaults System.
+type Preferences struct {
+	// Locale is the "AppleLocale" with preferences applied as BCP 47
+	// unicode extensions like "en-US-u-fw-mon-hc-h23-ms-metric".
+	Locale language.Tag
+	// MeasurementSystem is "metric", "ussystem" or "uksystem" from
+	// "AppleMeasurementUnits" and "AppleMetricUnits", empty if not set.
+	MeasurementSystem string
+	// TemperatureUnit is "celsius" or "fahrenheit" from
+	// "AppleTemperatureUnit", empty if not set.
+	TemperatureUnit string
+	// FirstWeekday is the first day of week of calendars like "gregorian"
+	// from "AppleFirstWeekday".
+	FirstWeekday map[string]time.Weekday
+	// Force24Hour is "AppleICUForce24HourTime".
+	Force24Hour bool
+	// Force12Hour is "AppleICUForce12HourTime".
+	Force12Hour bool
+	// DateFormats are the custom date formats from
+	// "AppleICUDateFormatStrings" keyed by style from 1 (short) to 4 (full).
+	DateFormats map[int]string
+	// TimeFormats are the custom time formats from
+	// "AppleICUTimeFormatStrings" keyed by style from 1 (short) to 4 (full).
+	TimeFormats map[int]string
+}
+
+// Apply will apply the preferences to tag as BCP 47 unicode extensions,
+// the extensions set in tag are overridden.
+//
+// Custom date and time formats could not be represented and are ignored.
+func (p Preferences) Apply(tag language.Tag) language.Tag {
+	set := func(key, value string) {
+		if t, err := tag.SetTypeForKey(key, value); err == nil {
+			tag = t
+		}
+	}
+
+	if p.MeasurementSystem != "" {
+		set("ms", p.MeasurementSystem)
+	}
+	switch p.TemperatureUnit {
+	case "celsius", "kelvin":
+		set("mu", p.TemperatureUnit)
+	case "fahrenheit":
+		set("mu", "fahrenhe")
+	}
+	if wd, ok := p.FirstWeekday["gregorian"]; ok {
+		set("fw", strings.ToLower(wd.String()[:3]))
+	}
+	switch {
+	case p.Force24Hour:
+		set("hc", "h23")
+	case p.Force12Hour:
+		set("hc", "h12")
+	}
+	return tag
+}
+
+// parseApplePreferences will parse the regional format preferences in
+// prefs, unknown or malformed values are ignored.
+func parseApplePreferences(prefs map[string]any) Preferences {
+	var p Preferences
+
+	// AppleMetricUnits decides metric or not, and AppleMeasurementUnits
+	// "Inches" with metric units is the UK system.
+	units, _ := prefs["AppleMeasurementUnits"].(string)
+	if metric, ok := prefs["AppleMetricUnits"].(bool); ok {
+		switch {
+		case !metric:
+			p.MeasurementSystem = "ussystem"
+		case units == "Inches":
+			p.MeasurementSystem = "uksystem"
+		default:
+			p.MeasurementSystem = "metric"
+		}
+	} else {
+		switch units {
+		case "Centimeters":
+			p.MeasurementSystem = "metric"
+		case "Inches":
+			p.MeasurementSystem = "ussystem"
+		}
+	}
+
+	if s, ok := prefs["AppleTemperatureUnit"].(string); ok {
+		switch s = strings.ToLower(s); s {
+		case "celsius", "fahrenheit", "kelvin":
+			p.TemperatureUnit = s
+		}
+	}
+
+	// Weekdays are numbered from 1 (Sunday) to 7 (Saturday).
+	if m, ok := prefs["AppleFirstWeekday"].(map[string]any); ok {
+		for cal, v := range m {
+			n, ok := v.(int64)
+			if !ok || n < 1 || n > 7 {
+				continue
+			}
+			if p.FirstWeekday == nil {
+				p.FirstWeekday = make(map[string]time.Weekday)
+			}
+			p.FirstWeekday[cal] = time.Weekday(n - 1)
+		}
+	}
+
+	p.Force24Hour, _ = prefs["AppleICUForce24HourTime"].(bool)
+	p.Force12Hour, _ = prefs["AppleICUForce12HourTime"].(bool)
+	p.DateFormats = parseAppleFormatStrings(prefs["AppleICUDateFormatStrings"])
+	p.TimeFormats = parseAppleFormatStrings(prefs["AppleICUTimeFormatStrings"])
+
+	locale := language.Und
+	if s, ok := appleLocale(prefs); ok {
+		if tag, err := language.Parse(Canonicalize(s)); err == nil {
+			locale = tag
+		}
+	}
+	p.Locale = p.Apply(locale)
+	return p
+}
+
+// parseAppleFormatStrings will parse format strings like {"1": "y-MM-dd"}
+// keyed by style, nil will be returned if there is no format.
+func parseAppleFormatStrings(v any) map[int]string {
+	m, _ := v.(map[string]any)
+
+	var formats map[int]string
+	for k, v := range m {
+		style, err := strconv.Atoi(k)
+		s, ok := v.(string)
+		if err != nil || style < 1 || style > 4 || !ok || s == "" {
+			continue
+		}
+		if formats == nil {
+			formats = make(map[int]string)
+		}
+		formats[style] = s
+	}
+	return formats
+}
+
+// readAppleGlobalPreferences will read and merge the global preferences,
+// keys in the user level one override the system level one.
+func readAppleGlobalPreferences(c *Config) (map[string]any, error) {
+	paths := getAppleGlobalPreferencesPaths(c)
+
+	var prefs map[string]any
+	for i := len(paths) - 1; i >= 0; i-- {
+		// Preferences could be missing or unreadable, try the next one.
+		m, err := readApplePreferences(c, paths[i])
+		if err != nil {
+			continue
+		}
+		if prefs == nil {
+			prefs = make(map[string]any, len(m))
+		}
+		for k, v := range m {
+			prefs[k] = v
+		}
+	}
+	if prefs == nil {
+		return nil, ErrNotDetected
+	}
+	return prefs, nil
+}
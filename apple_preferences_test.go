@@ -0,0 +1,127 @@
+package locale
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func TestParseApplePreferences(t *testing.T) {
+	for _, name := range []string{"GlobalPreferences.plist", "GlobalPreferences.xml.plist"} {
+		t.Run(name, func(t *testing.T) {
+			v, err := decodePlist(readPlistFixture(t, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expect := Preferences{
+				Locale:            language.MustParse("en-US-u-fw-mon-hc-h23-ms-metric-mu-celsius-rg-gbzzzz"),
+				MeasurementSystem: "metric",
+				TemperatureUnit:   "celsius",
+				FirstWeekday:      map[string]time.Weekday{"gregorian": time.Monday},
+				Force24Hour:       true,
+				DateFormats:       map[int]string{1: "y-MM-dd"},
+			}
+			if got := parseApplePreferences(v.(map[string]any)); !reflect.DeepEqual(got, expect) {
+				t.Errorf("parseApplePreferences() = %+v, want %+v", got, expect)
+			}
+		})
+	}
+}
+
+func TestParseApplePreferencesValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefs  map[string]any
+		expect Preferences
+	}{
+		{
+			"empty",
+			map[string]any{},
+			Preferences{Locale: language.Und},
+		},
+		{
+			"us",
+			map[string]any{
+				"AppleLocale":             "en_US",
+				"AppleMeasurementUnits":   "Inches",
+				"AppleMetricUnits":        false,
+				"AppleTemperatureUnit":    "Fahrenheit",
+				"AppleFirstWeekday":       map[string]any{"gregorian": int64(1), "japanese": int64(8)},
+				"AppleICUForce12HourTime": true,
+			},
+			Preferences{
+				Locale:            language.MustParse("en-US-u-fw-sun-hc-h12-ms-ussystem-mu-fahrenhe"),
+				MeasurementSystem: "ussystem",
+				TemperatureUnit:   "fahrenheit",
+				FirstWeekday:      map[string]time.Weekday{"gregorian": time.Sunday},
+				Force12Hour:       true,
+			},
+		},
+		{
+			"uk",
+			map[string]any{
+				"AppleLocale":           "en_GB@fw=sun",
+				"AppleMeasurementUnits": "Inches",
+				"AppleMetricUnits":      true,
+				"AppleFirstWeekday":     map[string]any{"gregorian": int64(2)},
+			},
+			Preferences{
+				Locale:            language.MustParse("en-GB-u-fw-mon-ms-uksystem"),
+				MeasurementSystem: "uksystem",
+				FirstWeekday:      map[string]time.Weekday{"gregorian": time.Monday},
+			},
+		},
+		{
+			"units only",
+			map[string]any{
+				"AppleMeasurementUnits":     "Centimeters",
+				"AppleTemperatureUnit":      "Rankine",
+				"AppleICUTimeFormatStrings": map[string]any{"2": "HH:mm:ss", "5": "H", "x": "H", "3": int64(1)},
+			},
+			Preferences{
+				Locale:            language.MustParse("und-u-ms-metric"),
+				MeasurementSystem: "metric",
+				TimeFormats:       map[int]string{2: "HH:mm:ss"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseApplePreferences(tt.prefs); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("parseApplePreferences() = %+v, want %+v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestReadAppleGlobalPreferences(t *testing.T) {
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"HOME": "/Users/alice"}),
+		FS: fstest.MapFS{
+			"Users/alice/Library/Preferences/.GlobalPreferences.plist": {Data: []byte(
+				"<plist><dict><key>AppleLocale</key><string>de_DE</string></dict></plist>",
+			)},
+			"Library/Preferences/.GlobalPreferences.plist": {Data: []byte(
+				"<plist><dict><key>AppleLocale</key><string>en_US</string><key>AppleMetricUnits</key><true/></dict></plist>",
+			)},
+		},
+	}
+	prefs, err := readAppleGlobalPreferences(c)
+	if err != nil {
+		t.Fatalf("readAppleGlobalPreferences() error = %v", err)
+	}
+	expect := map[string]any{"AppleLocale": "de_DE", "AppleMetricUnits": true}
+	if !reflect.DeepEqual(prefs, expect) {
+		t.Errorf("readAppleGlobalPreferences() = %v, want %v", prefs, expect)
+	}
+
+	_, err = readAppleGlobalPreferences(&Config{FS: fstest.MapFS{}})
+	if !errors.Is(err, ErrNotDetected) {
+		t.Errorf("readAppleGlobalPreferences() error = %v, want %v", err, ErrNotDetected)
+	}
+}
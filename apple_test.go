@@ -0,0 +1,118 @@
+package locale
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGetAppleGlobalPreferencesPaths(t *testing.T) {
+	c := &Config{LookupEnv: mapLookup(map[string]string{"HOME": "/Users/alice"})}
+	expect := []string{
+		"/Users/alice/Library/Preferences/.GlobalPreferences.plist",
+		"/Library/Preferences/.GlobalPreferences.plist",
+	}
+	if got := getAppleGlobalPreferencesPaths(c); !reflect.DeepEqual(got, expect) {
+		t.Errorf("getAppleGlobalPreferencesPaths() = %v, want %v", got, expect)
+	}
+
+	c = &Config{LookupEnv: mapLookup(map[string]string{})}
+	expect = []string{"/Library/Preferences/.GlobalPreferences.plist"}
+	if got := getAppleGlobalPreferencesPaths(c); !reflect.DeepEqual(got, expect) {
+		t.Errorf("getAppleGlobalPreferencesPaths() = %v, want %v", got, expect)
+	}
+}
+
+func TestGetAppleBundlePreferencesPaths(t *testing.T) {
+	c := &Config{LookupEnv: mapLookup(map[string]string{"HOME": "/Users/alice"})}
+
+	tests := []struct {
+		bundleID string
+		expect   []string
+	}{
+		{"com.example.app", []string{
+			"/Users/alice/Library/Containers/com.example.app/Data/Library/Preferences/com.example.app.plist",
+			"/Users/alice/Library/Preferences/com.example.app.plist",
+			"/Library/Preferences/com.example.app.plist",
+		}},
+		{"", nil},
+		{"..", nil},
+		{"../.GlobalPreferences", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.bundleID, func(t *testing.T) {
+			if got := getAppleBundlePreferencesPaths(c, tt.bundleID); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("getAppleBundlePreferencesPaths() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestReadApplePreferences(t *testing.T) {
+	c := &Config{FS: fstest.MapFS{
+		"binary.plist": {Data: readPlistFixture(t, "GlobalPreferences.plist")},
+		"xml.plist":    {Data: readPlistFixture(t, "GlobalPreferences.xml.plist")},
+		"array.plist":  {Data: []byte("<plist><array><string>en</string></array></plist>")},
+		"empty.plist":  {Data: []byte("<plist><dict></dict></plist>")},
+	}}
+
+	for _, fp := range []string{"/binary.plist", "/xml.plist"} {
+		t.Run(fp, func(t *testing.T) {
+			prefs, err := readApplePreferences(c, fp)
+			if err != nil {
+				t.Fatalf("readApplePreferences() error = %v", err)
+			}
+			if s, ok := appleLocale(prefs); !ok || s != "en_US@rg=gbzzzz" {
+				t.Errorf("appleLocale() = %v, %v", s, ok)
+			}
+			expect := []string{"en-US", "zh-Hans-CN", "ja-JP"}
+			if langs, ok := appleLanguages(prefs); !ok || !reflect.DeepEqual(langs, expect) {
+				t.Errorf("appleLanguages() = %v, want %v", langs, expect)
+			}
+		})
+	}
+
+	prefs, err := readApplePreferences(c, "/empty.plist")
+	if err != nil {
+		t.Fatalf("readApplePreferences() error = %v", err)
+	}
+	if _, ok := appleLocale(prefs); ok {
+		t.Error("appleLocale() expects not found")
+	}
+	if _, ok := appleLanguages(prefs); ok {
+		t.Error("appleLanguages() expects not found")
+	}
+
+	if _, err = readApplePreferences(c, "/array.plist"); err == nil {
+		t.Error("readApplePreferences() expects error for non-dict plist")
+	}
+	if _, err = readApplePreferences(c, "/missing.plist"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("readApplePreferences() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestParseICUKeywords(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect []string
+	}{
+		{"", nil},
+		{"rg=gbzzzz", []string{"rg-gbzzzz"}},
+		{"rg=gbzzzz;calendar=japanese", []string{"ca-japanese", "rg-gbzzzz"}},
+		{"currency=EUR;hours=h23;fw=mon", []string{"cu-eur", "fw-mon", "hc-h23"}},
+		{"measure=imperial;calendar=gregorian", []string{"ca-gregory", "ms-uksystem"}},
+		{"calendar=islamic-civil;numbers=latn", []string{"ca-islamic-civil", "nu-latn"}},
+		{"ca=japanese;calendar=buddhist", []string{"ca-japanese"}},
+		{"unknown=value;rg=x;currency", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := parseICUKeywords(tt.input)
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("parseICUKeywords() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
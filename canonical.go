@@ -0,0 +1,435 @@
+package locale
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Canonicalize will convert locale identifier s into BCP 47 form as
+// CFLocale does, so that legacy values could be parsed by language.Make:
+//   - Old Apple names like "English" or "zh.Ha-T_TW" are mapped to modern codes
+//   - ISO 639-2 and deprecated codes like "ger" or "iw_IL" are mapped to ISO 639-1
+//   - Chinese without script like "zh_TW" is upgraded to "zh-Hant-TW"
+//   - ICU keywords like "en_US@rg=gbzzzz" are converted to unicode extensions
+//   - POSIX names like "sr_RS.UTF-8@latin" are parsed as env does, codeset is
+//     dropped and well-known modifiers are converted
+//
+// Values which could not be canonicalized are returned with "_" replaced by "-".
+//
+// ref: https://github.com/apple/swift-corelibs-foundation/blob/main/CoreFoundation/Locale.subproj/CFLocaleIdentifier.c
+func Canonicalize(s string) string {
+	s = strings.TrimSpace(s)
+	base, keywords, _ := strings.Cut(s, "@")
+	// POSIX modifiers like "@latin" are not ICU keywords.
+	if !strings.Contains(keywords, "=") {
+		base, keywords = s, ""
+	}
+	if v, ok := oldAppleLocaleToCanonical[base]; ok {
+		base = v
+	} else if strings.ContainsAny(base, ".@") {
+		base = parseEnvLc(base)
+	}
+
+	tags := strings.FieldsFunc(base, func(r rune) bool { return r == '_' || r == '-' })
+	if len(tags) == 0 {
+		return s
+	}
+
+	// The longest prefix wins, like "zh-min-nan" over "zh".
+	for n := min(len(tags), 3); n > 0; n-- {
+		if v, ok := localeStringPrefixToCanonical[strings.ToLower(strings.Join(tags[:n], "-"))]; ok {
+			tags = append(strings.Split(v, "-"), tags[n:]...)
+			break
+		}
+	}
+
+	if len(tags) > 1 && strings.EqualFold(tags[0], "zh") {
+		if script, ok := localeStringRegionToDefaults[strings.ToUpper(tags[1])]; ok {
+			tags = append([]string{tags[0], script}, tags[1:]...)
+		}
+	}
+
+	s = strings.Join(tags, "-")
+	if exts := parseICUKeywords(keywords); len(exts) > 0 {
+		s += "-u-" + strings.Join(exts, "-")
+	}
+	return s
+}
+
+// makeTag will make language.Tag from detected value s after canonicalized.
+func makeTag(s string) language.Tag {
+	return language.Make(Canonicalize(s))
+}
+
+// localeStringRegionToDefaults is the script of Chinese which is implied by
+// region.
+var localeStringRegionToDefaults = map[string]string{
+	"CN": "Hans",
+	"HK": "Hant",
+	"MO": "Hant",
+	"SG": "Hans",
+	"TW": "Hant",
+}
+
+// localeStringPrefixToCanonical maps ISO 639-2 codes, deprecated codes and
+// grandfathered tags to their canonical codes, keys are lower cased and
+// "-" separated.
+var localeStringPrefixToCanonical = map[string]string{
+	// ISO 639-2 bibliographic and terminology codes.
+	"afr": "af",
+	"alb": "sq",
+	"amh": "am",
+	"ara": "ar",
+	"arm": "hy",
+	"asm": "as",
+	"aym": "ay",
+	"aze": "az",
+	"baq": "eu",
+	"bel": "be",
+	"ben": "bn",
+	"bod": "bo",
+	"bos": "bs",
+	"bre": "br",
+	"bul": "bg",
+	"bur": "my",
+	"cat": "ca",
+	"ces": "cs",
+	"chi": "zh",
+	"cym": "cy",
+	"cze": "cs",
+	"dan": "da",
+	"deu": "de",
+	"dut": "nl",
+	"dzo": "dz",
+	"ell": "el",
+	"eng": "en",
+	"epo": "eo",
+	"est": "et",
+	"eus": "eu",
+	"fao": "fo",
+	"fas": "fa",
+	"fin": "fi",
+	"fra": "fr",
+	"fre": "fr",
+	"geo": "ka",
+	"ger": "de",
+	"gla": "gd",
+	"gle": "ga",
+	"glg": "gl",
+	"glv": "gv",
+	"gre": "el",
+	"grn": "gn",
+	"guj": "gu",
+	"heb": "he",
+	"hin": "hi",
+	"hrv": "hr",
+	"hun": "hu",
+	"hye": "hy",
+	"ice": "is",
+	"iku": "iu",
+	"ind": "id",
+	"isl": "is",
+	"ita": "it",
+	"jav": "jv",
+	"jpn": "ja",
+	"kal": "kl",
+	"kan": "kn",
+	"kas": "ks",
+	"kat": "ka",
+	"kaz": "kk",
+	"khm": "km",
+	"kin": "rw",
+	"kir": "ky",
+	"kor": "ko",
+	"kur": "ku",
+	"lao": "lo",
+	"lat": "la",
+	"lav": "lv",
+	"lit": "lt",
+	"mac": "mk",
+	"mal": "ml",
+	"mar": "mr",
+	"may": "ms",
+	"mkd": "mk",
+	"mlg": "mg",
+	"mlt": "mt",
+	"mon": "mn",
+	"msa": "ms",
+	"mya": "my",
+	"nep": "ne",
+	"nld": "nl",
+	"nno": "nn",
+	"nob": "nb",
+	"nor": "nb",
+	"nya": "ny",
+	"ori": "or",
+	"orm": "om",
+	"pan": "pa",
+	"per": "fa",
+	"pol": "pl",
+	"por": "pt",
+	"pus": "ps",
+	"que": "qu",
+	"ron": "ro",
+	"rum": "ro",
+	"run": "rn",
+	"rus": "ru",
+	"san": "sa",
+	"sin": "si",
+	"slk": "sk",
+	"slo": "sk",
+	"slv": "sl",
+	"sme": "se",
+	"snd": "sd",
+	"som": "so",
+	"spa": "es",
+	"sqi": "sq",
+	"srp": "sr",
+	"sun": "su",
+	"swa": "sw",
+	"swe": "sv",
+	"tam": "ta",
+	"tat": "tt",
+	"tel": "te",
+	"tgk": "tg",
+	"tgl": "fil",
+	"tha": "th",
+	"tib": "bo",
+	"tir": "ti",
+	"ton": "to",
+	"tuk": "tk",
+	"tur": "tr",
+	"uig": "ug",
+	"ukr": "uk",
+	"urd": "ur",
+	"uzb": "uz",
+	"vie": "vi",
+	"wel": "cy",
+	"yid": "yi",
+	"zho": "zh",
+
+	// Deprecated codes.
+	"in": "id",
+	"iw": "he",
+	"ji": "yi",
+	"jw": "jv",
+	"mo": "ro",
+	"no": "nb",
+	"sh": "sr-Latn",
+	"tl": "fil",
+
+	// Grandfathered tags.
+	"art-lojban": "jbo",
+	"i-ami":      "ami",
+	"i-bnn":      "bnn",
+	"i-hak":      "hak",
+	"i-klingon":  "tlh",
+	"i-lux":      "lb",
+	"i-navajo":   "nv",
+	"i-pwn":      "pwn",
+	"i-tao":      "tao",
+	"i-tay":      "tay",
+	"i-tsu":      "tsu",
+	"no-bok":     "nb",
+	"no-nyn":     "nn",
+	"zh-guoyu":   "zh",
+	"zh-hakka":   "hak",
+	"zh-min-nan": "nan",
+	"zh-xiang":   "hsn",
+}
+
+// oldAppleLocaleToCanonical is borrowed from swift-corelibs-foundation's CFLocaleIdentifier.c
+//
+// Old Apple devices could return "English" instead of "en-US", and old Mac
+// script and region codes like "zh.Ha-T_TW" or "en_??", this map will make
+// them canonical.
+//
+// refs:
+//   - CFLocaleIdentifier.c: https://github.com/apple/swift-corelibs-foundation/blob/main/CoreFoundation/Locale.subproj/CFLocaleIdentifier.c
+var oldAppleLocaleToCanonical = map[string]string{
+	"Afrikaans":             "af",         //                      # __CFBundleLanguageNamesArray
+	"Albanian":              "sq",         //                      # __CFBundleLanguageNamesArray
+	"Amharic":               "am",         //                      # __CFBundleLanguageNamesArray
+	"Arabic":                "ar",         //                      # __CFBundleLanguageNamesArray
+	"Armenian":              "hy",         //                      # __CFBundleLanguageNamesArray
+	"Assamese":              "as",         //                      # __CFBundleLanguageNamesArray
+	"Aymara":                "ay",         //                      # __CFBundleLanguageNamesArray
+	"Azerbaijani":           "az",         // -Arab,-Cyrl,-Latn?   # __CFBundleLanguageNamesArray (had 3 entries "Azerbaijani" for "az-Arab", "az-Cyrl", "az-Latn")
+	"Basque":                "eu",         //                      # __CFBundleLanguageNamesArray
+	"Belarusian":            "be",         //                      # handle other names
+	"Belorussian":           "be",         //                      # handle other names
+	"Bengali":               "bn",         //                      # __CFBundleLanguageNamesArray
+	"Brazilian Portugese":   "pt-BR",      //                      # from Installer.app Info.plist IFLanguages key, misspelled
+	"Brazilian Portuguese":  "pt-BR",      //                      # correct spelling for above
+	"Breton":                "br",         //                      # __CFBundleLanguageNamesArray
+	"Bulgarian":             "bg",         //                      # __CFBundleLanguageNamesArray
+	"Burmese":               "my",         //                      # __CFBundleLanguageNamesArray
+	"Byelorussian":          "be",         //                      # __CFBundleLanguageNamesArray
+	"Catalan":               "ca",         //                      # __CFBundleLanguageNamesArray
+	"Chewa":                 "ny",         //                      # handle other names
+	"Chichewa":              "ny",         //                      # handle other names
+	"Chinese":               "zh",         // -Hans,-Hant?         # __CFBundleLanguageNamesArray (had 2 entries "Chinese" for "zh-Hant", "zh-Hans")
+	"Chinese, Simplified":   "zh-Hans",    //                      # from Installer.app Info.plist IFLanguages key
+	"Chinese, Traditional":  "zh-Hant",    //                      # correct spelling for below
+	"Chinese, Tradtional":   "zh-Hant",    //                      # from Installer.app Info.plist IFLanguages key, misspelled
+	"Croatian":              "hr",         //                      # __CFBundleLanguageNamesArray
+	"Czech":                 "cs",         //                      # __CFBundleLanguageNamesArray
+	"Danish":                "da",         //                      # __CFBundleLanguageNamesArray
+	"Dutch":                 "nl",         //                      # __CFBundleLanguageNamesArray (had 2 entries "Dutch" for "nl", "nl-BE")
+	"Dzongkha":              "dz",         //                      # __CFBundleLanguageNamesArray
+	"English":               "en",         //                      # __CFBundleLanguageNamesArray
+	"Esperanto":             "eo",         //                      # __CFBundleLanguageNamesArray
+	"Estonian":              "et",         //                      # __CFBundleLanguageNamesArray
+	"Faroese":               "fo",         //                      # __CFBundleLanguageNamesArray
+	"Farsi":                 "fa",         //                      # __CFBundleLanguageNamesArray
+	"Finnish":               "fi",         //                      # __CFBundleLanguageNamesArray
+	"Flemish":               "nl-BE",      //                      # handle other names
+	"French":                "fr",         //                      # __CFBundleLanguageNamesArray
+	"Galician":              "gl",         //                      # __CFBundleLanguageNamesArray
+	"Gallegan":              "gl",         //                      # handle other names
+	"Georgian":              "ka",         //                      # __CFBundleLanguageNamesArray
+	"German":                "de",         //                      # __CFBundleLanguageNamesArray
+	"Greek":                 "el",         //                      # __CFBundleLanguageNamesArray (had 2 entries "Greek" for "el", "grc")
+	"Greenlandic":           "kl",         //                      # __CFBundleLanguageNamesArray
+	"Guarani":               "gn",         //                      # __CFBundleLanguageNamesArray
+	"Gujarati":              "gu",         //                      # __CFBundleLanguageNamesArray
+	"Hawaiian":              "haw",        //                      # handle new languages
+	"Hebrew":                "he",         //                      # __CFBundleLanguageNamesArray
+	"Hindi":                 "hi",         //                      # __CFBundleLanguageNamesArray
+	"Hungarian":             "hu",         //                      # __CFBundleLanguageNamesArray
+	"Icelandic":             "is",         //                      # __CFBundleLanguageNamesArray
+	"Indonesian":            "id",         //                      # __CFBundleLanguageNamesArray
+	"Inuktitut":             "iu",         //                      # __CFBundleLanguageNamesArray
+	"Irish":                 "ga",         //                      # __CFBundleLanguageNamesArray (had 2 entries "Irish" for "ga", "ga-dots")
+	"Italian":               "it",         //                      # __CFBundleLanguageNamesArray
+	"Japanese":              "ja",         //                      # __CFBundleLanguageNamesArray
+	"Javanese":              "jv",         //                      # __CFBundleLanguageNamesArray
+	"Kalaallisut":           "kl",         //                      # handle other names
+	"Kannada":               "kn",         //                      # __CFBundleLanguageNamesArray
+	"Kashmiri":              "ks",         //                      # __CFBundleLanguageNamesArray
+	"Kazakh":                "kk",         //                      # __CFBundleLanguageNamesArray
+	"Khmer":                 "km",         //                      # __CFBundleLanguageNamesArray
+	"Kinyarwanda":           "rw",         //                      # __CFBundleLanguageNamesArray
+	"Kirghiz":               "ky",         //                      # __CFBundleLanguageNamesArray
+	"Korean":                "ko",         //                      # __CFBundleLanguageNamesArray
+	"Kurdish":               "ku",         //                      # __CFBundleLanguageNamesArray
+	"Lao":                   "lo",         //                      # __CFBundleLanguageNamesArray
+	"Latin":                 "la",         //                      # __CFBundleLanguageNamesArray
+	"Latvian":               "lv",         //                      # __CFBundleLanguageNamesArray
+	"Lithuanian":            "lt",         //                      # __CFBundleLanguageNamesArray
+	"Macedonian":            "mk",         //                      # __CFBundleLanguageNamesArray
+	"Malagasy":              "mg",         //                      # __CFBundleLanguageNamesArray
+	"Malay":                 "ms",         // -Latn,-Arab?         # __CFBundleLanguageNamesArray (had 2 entries "Malay" for "ms-Latn", "ms-Arab")
+	"Malayalam":             "ml",         //                      # __CFBundleLanguageNamesArray
+	"Maltese":               "mt",         //                      # __CFBundleLanguageNamesArray
+	"Manx":                  "gv",         //                      # __CFBundleLanguageNamesArray
+	"Marathi":               "mr",         //                      # __CFBundleLanguageNamesArray
+	"Moldavian":             "mo",         //                      # __CFBundleLanguageNamesArray
+	"Mongolian":             "mn",         // -Mong,-Cyrl?         # __CFBundleLanguageNamesArray (had 2 entries "Mongolian" for "mn-Mong", "mn-Cyrl")
+	"Nepali":                "ne",         //                      # __CFBundleLanguageNamesArray
+	"Norwegian":             "nb",         //                      # __CFBundleLanguageNamesArray (had "Norwegian" mapping to "no")
+	"Nyanja":                "ny",         //                      # __CFBundleLanguageNamesArray
+	"Nynorsk":               "nn",         //                      # handle other names (no entry in __CFBundleLanguageNamesArray)
+	"Oriya":                 "or",         //                      # __CFBundleLanguageNamesArray
+	"Oromo":                 "om",         //                      # __CFBundleLanguageNamesArray
+	"Panjabi":               "pa",         //                      # handle other names
+	"Pashto":                "ps",         //                      # __CFBundleLanguageNamesArray
+	"Persian":               "fa",         //                      # handle other names
+	"Polish":                "pl",         //                      # __CFBundleLanguageNamesArray
+	"Portuguese":            "pt",         //                      # __CFBundleLanguageNamesArray
+	"Portuguese, Brazilian": "pt-BR",      //                      # handle other names
+	"Punjabi":               "pa",         //                      # __CFBundleLanguageNamesArray
+	"Pushto":                "ps",         //                      # handle other names
+	"Quechua":               "qu",         //                      # __CFBundleLanguageNamesArray
+	"Romanian":              "ro",         //                      # __CFBundleLanguageNamesArray
+	"Ruanda":                "rw",         //                      # handle other names
+	"Rundi":                 "rn",         //                      # __CFBundleLanguageNamesArray
+	"Russian":               "ru",         //                      # __CFBundleLanguageNamesArray
+	"Sami":                  "se",         //                      # __CFBundleLanguageNamesArray
+	"Sanskrit":              "sa",         //                      # __CFBundleLanguageNamesArray
+	"Scottish":              "gd",         //                      # __CFBundleLanguageNamesArray
+	"Serbian":               "sr",         //                      # __CFBundleLanguageNamesArray
+	"Simplified Chinese":    "zh-Hans",    //                      # handle other names
+	"Sindhi":                "sd",         //                      # __CFBundleLanguageNamesArray
+	"Sinhalese":             "si",         //                      # __CFBundleLanguageNamesArray
+	"Slovak":                "sk",         //                      # __CFBundleLanguageNamesArray
+	"Slovenian":             "sl",         //                      # __CFBundleLanguageNamesArray
+	"Somali":                "so",         //                      # __CFBundleLanguageNamesArray
+	"Spanish":               "es",         //                      # __CFBundleLanguageNamesArray
+	"Sundanese":             "su",         //                      # __CFBundleLanguageNamesArray
+	"Swahili":               "sw",         //                      # __CFBundleLanguageNamesArray
+	"Swedish":               "sv",         //                      # __CFBundleLanguageNamesArray
+	"Tagalog":               "fil",        //                      # __CFBundleLanguageNamesArray
+	"Tajik":                 "tg",         //                      # handle other names
+	"Tajiki":                "tg",         //                      # __CFBundleLanguageNamesArray
+	"Tamil":                 "ta",         //                      # __CFBundleLanguageNamesArray
+	"Tatar":                 "tt",         //                      # __CFBundleLanguageNamesArray
+	"Telugu":                "te",         //                      # __CFBundleLanguageNamesArray
+	"Thai":                  "th",         //                      # __CFBundleLanguageNamesArray
+	"Tibetan":               "bo",         //                      # __CFBundleLanguageNamesArray
+	"Tigrinya":              "ti",         //                      # __CFBundleLanguageNamesArray
+	"Tongan":                "to",         //                      # __CFBundleLanguageNamesArray
+	"Traditional Chinese":   "zh-Hant",    //                      # handle other names
+	"Turkish":               "tr",         //                      # __CFBundleLanguageNamesArray
+	"Turkmen":               "tk",         //                      # __CFBundleLanguageNamesArray
+	"Uighur":                "ug",         //                      # __CFBundleLanguageNamesArray
+	"Ukrainian":             "uk",         //                      # __CFBundleLanguageNamesArray
+	"Urdu":                  "ur",         //                      # __CFBundleLanguageNamesArray
+	"Uzbek":                 "uz",         //                      # __CFBundleLanguageNamesArray
+	"Vietnamese":            "vi",         //                      # __CFBundleLanguageNamesArray
+	"Welsh":                 "cy",         //                      # __CFBundleLanguageNamesArray
+	"Yiddish":               "yi",         //                      # __CFBundleLanguageNamesArray
+	"ar_??":                 "ar",         //                      # from old MapScriptInfoAndISOCodes
+	"az.Ar":                 "az-Arab",    //                      # from old LocaleRefGetPartString
+	"az.Cy":                 "az-Cyrl",    //                      # from old LocaleRefGetPartString
+	"az.La":                 "az",         //                      # from old LocaleRefGetPartString
+	"be_??":                 "be_BY",      //                      # from old MapScriptInfoAndISOCodes
+	"bn_??":                 "bn",         //                      # from old LocaleRefGetPartString
+	"bo_??":                 "bo",         //                      # from old MapScriptInfoAndISOCodes
+	"br_??":                 "br",         //                      # from old MapScriptInfoAndISOCodes
+	"cy_??":                 "cy",         //                      # from old MapScriptInfoAndISOCodes
+	"de-96":                 "de-1996",    //                      # from old MapScriptInfoAndISOCodes                     // <1.9>
+	"de_96":                 "de-1996",    //                      # from old MapScriptInfoAndISOCodes                     // <1.9>
+	"de_??":                 "de-1996",    //                      # from old MapScriptInfoAndISOCodes
+	"el.El-P":               "grc",        //                      # from old LocaleRefGetPartString
+	"en-ascii":              "en_001",     //                      # from earlier version of tables in this file!
+	"en_??":                 "en_001",     //                      # from old MapScriptInfoAndISOCodes
+	"eo_??":                 "eo",         //                      # from old MapScriptInfoAndISOCodes
+	"es_??":                 "es_419",     //                      # from old MapScriptInfoAndISOCodes
+	"es_XL":                 "es_419",     //                      # from earlier version of tables in this file!
+	"fr_??":                 "fr_001",     //                      # from old MapScriptInfoAndISOCodes
+	"ga-dots":               "ga-Latg",    //                      # from earlier version of tables in this file!          // <1.8>
+	"ga-dots_IE":            "ga-Latg_IE", //                      # from earlier version of tables in this file!          // <1.8>
+	"ga.Lg":                 "ga-Latg",    //                      # from old LocaleRefGetPartString                       // <1.8>
+	"ga.Lg_IE":              "ga-Latg_IE", //                      # from old LocaleRefGetPartString                       // <1.8>
+	"gd_??":                 "gd",         //                      # from old MapScriptInfoAndISOCodes
+	"gv_??":                 "gv",         //                      # from old MapScriptInfoAndISOCodes
+	"jv.La":                 "jv",         //                      # logical extension                                     // <1.9>
+	"jw.La":                 "jv",         //                      # from old LocaleRefGetPartString
+	"kk.Cy":                 "kk",         //                      # from old LocaleRefGetPartString
+	"kl.La":                 "kl",         //                      # from old LocaleRefGetPartString
+	"kl.La_GL":              "kl_GL",      //                      # from old LocaleRefGetPartString                       // <1.9>
+	"lp_??":                 "se",         //                      # from old MapScriptInfoAndISOCodes
+	"mk_??":                 "mk_MK",      //                      # from old MapScriptInfoAndISOCodes
+	"mn.Cy":                 "mn",         //                      # from old LocaleRefGetPartString
+	"mn.Mn":                 "mn-Mong",    //                      # from old LocaleRefGetPartString
+	"ms.Ar":                 "ms-Arab",    //                      # from old LocaleRefGetPartString
+	"ms.La":                 "ms",         //                      # from old LocaleRefGetPartString
+	"nl-be":                 "nl-BE",      //                      # from old LocaleRefGetPartString
+	"nl-be_BE":              "nl_BE",      //                      # from old LocaleRefGetPartString
+	"no-NO":                 "nb-NO",      //                      # not handled by localeStringPrefixToCanonical
+	"no-NO_NO":              "nb-NO_NO",   //                      # not handled by localeStringPrefixToCanonical
+	"pa_??":                 "pa",         //                      # from old LocaleRefGetPartString
+	"sa.Dv":                 "sa",         //                      # from old LocaleRefGetPartString
+	"sl_??":                 "sl_SI",      //                      # from old MapScriptInfoAndISOCodes
+	"sr_??":                 "sr_RS",      //                      # from old MapScriptInfoAndISOCodes						// <1.18>
+	"su.La":                 "su",         //                      # from old LocaleRefGetPartString
+	"yi.He":                 "yi",         //                      # from old LocaleRefGetPartString
+	"zh-simp":               "zh-Hans",    //                      # from earlier version of tables in this file!
+	"zh-trad":               "zh-Hant",    //                      # from earlier version of tables in this file!
+	"zh.Ha-S":               "zh-Hans",    //                      # from old LocaleRefGetPartString
+	"zh.Ha-S_CN":            "zh_CN",      //                      # from old LocaleRefGetPartString
+	"zh.Ha-T":               "zh-Hant",    //                      # from old LocaleRefGetPartString
+	"zh.Ha-T_TW":            "zh_TW",      //                      # from old LocaleRefGetPartString
+}
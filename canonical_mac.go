@@ -0,0 +1,247 @@
+package locale
+
+// CanonicalizeMacCodes will convert the old Mac OS Script Manager language
+// code lang and region code region into BCP 47 form as
+// CFLocaleCreateCanonicalLocaleIdentifierFromScriptManagerCodes does.
+//
+// The region code wins if it is known since it implies the language too, -1
+// could be used for the unknown one. false will be returned if neither of
+// them is known.
+//
+// ref: https://github.com/apple/swift-corelibs-foundation/blob/main/CoreFoundation/Locale.subproj/CFLocaleIdentifier.c
+func CanonicalizeMacCodes(lang, region int) (string, bool) {
+	if s, ok := regionCodeToLocaleString[region]; ok {
+		return Canonicalize(s), true
+	}
+	if s, ok := langCodeToLocaleString[lang]; ok {
+		return Canonicalize(s), true
+	}
+	return "", false
+}
+
+// langCodeToLocaleString maps the language codes (langXxx) in Script.h to
+// locale strings, codes without a locale are omitted.
+var langCodeToLocaleString = map[int]string{
+	0:   "en",      // langEnglish
+	1:   "fr",      // langFrench
+	2:   "de",      // langGerman
+	3:   "it",      // langItalian
+	4:   "nl",      // langDutch
+	5:   "sv",      // langSwedish
+	6:   "es",      // langSpanish
+	7:   "da",      // langDanish
+	8:   "pt",      // langPortuguese
+	9:   "nb",      // langNorwegian
+	10:  "he",      // langHebrew
+	11:  "ja",      // langJapanese
+	12:  "ar",      // langArabic
+	13:  "fi",      // langFinnish
+	14:  "el",      // langGreek
+	15:  "is",      // langIcelandic
+	16:  "mt",      // langMaltese
+	17:  "tr",      // langTurkish
+	18:  "hr",      // langCroatian
+	19:  "zh-Hant", // langTradChinese
+	20:  "ur",      // langUrdu
+	21:  "hi",      // langHindi
+	22:  "th",      // langThai
+	23:  "ko",      // langKorean
+	24:  "lt",      // langLithuanian
+	25:  "pl",      // langPolish
+	26:  "hu",      // langHungarian
+	27:  "et",      // langEstonian
+	28:  "lv",      // langLatvian
+	29:  "se",      // langSami
+	30:  "fo",      // langFaroese
+	31:  "fa",      // langFarsi
+	32:  "ru",      // langRussian
+	33:  "zh-Hans", // langSimpChinese
+	34:  "nl-BE",   // langFlemish
+	35:  "ga",      // langIrishGaelic
+	36:  "sq",      // langAlbanian
+	37:  "ro",      // langRomanian
+	38:  "cs",      // langCzech
+	39:  "sk",      // langSlovak
+	40:  "sl",      // langSlovenian
+	41:  "yi",      // langYiddish
+	42:  "sr",      // langSerbian
+	43:  "mk",      // langMacedonian
+	44:  "bg",      // langBulgarian
+	45:  "uk",      // langUkrainian
+	46:  "be",      // langByelorussian
+	47:  "uz",      // langUzbek
+	48:  "kk",      // langKazakh
+	49:  "az-Cyrl", // langAzerbaijani
+	50:  "az-Arab", // langAzerbaijanAr
+	51:  "hy",      // langArmenian
+	52:  "ka",      // langGeorgian
+	53:  "mo",      // langMoldavian
+	54:  "ky",      // langKirghiz
+	55:  "tg",      // langTajiki
+	56:  "tk",      // langTurkmen
+	57:  "mn-Mong", // langMongolian
+	58:  "mn",      // langMongolianCyr
+	59:  "ps",      // langPashto
+	60:  "ku",      // langKurdish
+	61:  "ks",      // langKashmiri
+	62:  "sd",      // langSindhi
+	63:  "bo",      // langTibetan
+	64:  "ne",      // langNepali
+	65:  "sa",      // langSanskrit
+	66:  "mr",      // langMarathi
+	67:  "bn",      // langBengali
+	68:  "as",      // langAssamese
+	69:  "gu",      // langGujarati
+	70:  "pa",      // langPunjabi
+	71:  "or",      // langOriya
+	72:  "ml",      // langMalayalam
+	73:  "kn",      // langKannada
+	74:  "ta",      // langTamil
+	75:  "te",      // langTelugu
+	76:  "si",      // langSinhalese
+	77:  "my",      // langBurmese
+	78:  "km",      // langKhmer
+	79:  "lo",      // langLao
+	80:  "vi",      // langVietnamese
+	81:  "id",      // langIndonesian
+	82:  "fil",     // langTagalog
+	83:  "ms",      // langMalayRoman
+	84:  "ms-Arab", // langMalayArabic
+	85:  "am",      // langAmharic
+	86:  "ti",      // langTigrinya
+	87:  "om",      // langOromo
+	88:  "so",      // langSomali
+	89:  "sw",      // langSwahili
+	90:  "rw",      // langKinyarwanda
+	91:  "rn",      // langRundi
+	92:  "ny",      // langNyanja
+	93:  "mg",      // langMalagasy
+	94:  "eo",      // langEsperanto
+	128: "cy",      // langWelsh
+	129: "eu",      // langBasque
+	130: "ca",      // langCatalan
+	131: "la",      // langLatin
+	132: "qu",      // langQuechua
+	133: "gn",      // langGuarani
+	134: "ay",      // langAymara
+	135: "tt",      // langTatar
+	136: "ug",      // langUighur
+	137: "dz",      // langDzongkha
+	138: "jv",      // langJavaneseRom
+	139: "su",      // langSundaneseRom
+	140: "gl",      // langGalician
+	141: "af",      // langAfrikaans
+	142: "br",      // langBreton
+	143: "iu",      // langInuktitut
+	144: "gd",      // langScottishGaelic
+	145: "gv",      // langManxGaelic
+	146: "ga-Latg", // langIrishGaelicScript
+	147: "to",      // langTongan
+	148: "grc",     // langGreekAncient
+	149: "kl",      // langGreenlandic
+	150: "az",      // langAzerbaijanRoman
+	151: "nn",      // langNynorsk
+}
+
+// regionCodeToLocaleString maps the region codes (verXxx) in Script.h to
+// locale strings, codes without a locale are omitted.
+var regionCodeToLocaleString = map[int]string{
+	0:   "en_US",      // verUS
+	1:   "fr_FR",      // verFrance
+	2:   "en_GB",      // verBritain
+	3:   "de_DE",      // verGermany
+	4:   "it_IT",      // verItaly
+	5:   "nl_NL",      // verNetherlands
+	6:   "nl_BE",      // verFlemish
+	7:   "sv_SE",      // verSweden
+	8:   "es_ES",      // verSpain
+	9:   "da_DK",      // verDenmark
+	10:  "pt_PT",      // verPortugal
+	11:  "fr_CA",      // verFrCanada
+	12:  "nb_NO",      // verNorway
+	13:  "he_IL",      // verIsrael
+	14:  "ja_JP",      // verJapan
+	15:  "en_AU",      // verAustralia
+	16:  "ar",         // verArabic
+	17:  "fi_FI",      // verFinland
+	18:  "fr_CH",      // verFrSwiss
+	19:  "de_CH",      // verGrSwiss
+	20:  "el_GR",      // verGreece
+	21:  "is_IS",      // verIceland
+	22:  "mt_MT",      // verMalta
+	23:  "el_CY",      // verCyprus
+	24:  "tr_TR",      // verTurkey
+	25:  "hr_HR",      // verYugoCroatian
+	26:  "nl_NL",      // verNetherlandsComma
+	27:  "nl_BE",      // verBelgiumLuxPoint
+	28:  "en_CA",      // verCanadaComma
+	29:  "en_CA",      // verCanadaPoint
+	30:  "pt_PT",      // vervariantPortugal
+	31:  "nb_NO",      // vervariantNorway
+	32:  "da_DK",      // vervariantDenmark
+	33:  "hi_IN",      // verIndiaHindi
+	34:  "ur_PK",      // verPakistanUrdu
+	35:  "tr_TR",      // verTurkishModified
+	36:  "it_CH",      // verItalianSwiss
+	37:  "en_001",     // verInternational
+	39:  "ro_RO",      // verRomania
+	40:  "grc",        // verGreekAncient
+	41:  "lt_LT",      // verLithuania
+	42:  "pl_PL",      // verPoland
+	43:  "hu_HU",      // verHungary
+	44:  "et_EE",      // verEstonia
+	45:  "lv_LV",      // verLatvia
+	46:  "se",         // verSami
+	47:  "fo_FO",      // verFaroeIsl
+	48:  "fa_IR",      // verIran
+	49:  "ru_RU",      // verRussia
+	50:  "ga_IE",      // verIreland
+	51:  "ko_KR",      // verKorea
+	52:  "zh_CN",      // verChina
+	53:  "zh_TW",      // verTaiwan
+	54:  "th_TH",      // verThailand
+	56:  "cs_CZ",      // verCzech
+	57:  "sk_SK",      // verSlovak
+	59:  "hu_HU",      // verMagyar
+	60:  "bn",         // verBengali
+	61:  "be_BY",      // verBelarus
+	62:  "uk_UA",      // verUkraine
+	64:  "el_GR",      // verGreeceAlt
+	65:  "sr_RS",      // verSerbian
+	66:  "sl_SI",      // verSlovenian
+	67:  "mk_MK",      // verMacedonian
+	68:  "hr_HR",      // verCroatia
+	70:  "de-1996",    // verGermanReformed
+	71:  "pt_BR",      // verBrazil
+	72:  "bg_BG",      // verBulgaria
+	73:  "ca_ES",      // verCatalonia
+	75:  "gd",         // verScottishGaelic
+	76:  "gv",         // verManxGaelic
+	77:  "br",         // verBreton
+	78:  "iu_CA",      // verNunavut
+	79:  "cy",         // verWelsh
+	81:  "ga-Latg_IE", // verIrishGaelicScript
+	82:  "en_CA",      // verEngCanada
+	83:  "dz_BT",      // verBhutan
+	84:  "hy_AM",      // verArmenian
+	85:  "ka_GE",      // verGeorgian
+	86:  "es_419",     // verSpLatinAmerica
+	88:  "to_TO",      // verTonga
+	91:  "fr_001",     // verFrenchUniversal
+	92:  "de_AT",      // verAustria
+	94:  "gu_IN",      // verGujarati
+	95:  "pa",         // verPunjabi
+	96:  "ur_IN",      // verIndiaUrdu
+	97:  "vi_VN",      // verVietnam
+	98:  "fr_BE",      // verFrBelgium
+	99:  "uz_UZ",      // verUzbek
+	100: "en_SG",      // verSingapore
+	101: "nn_NO",      // verNynorsk
+	102: "af_ZA",      // verAfrikaans
+	103: "eo",         // verEsperanto
+	104: "mr_IN",      // verMarathi
+	105: "bo",         // verTibetan
+	106: "ne_NP",      // verNepal
+	107: "kl",         // verGreenland
+	108: "en_IE",      // verIrelandEnglish
+}
@@ -0,0 +1,34 @@
+package locale
+
+import (
+	"testing"
+)
+
+func TestCanonicalizeMacCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   int
+		region int
+		expect string
+		ok     bool
+	}{
+		{"region wins", 1, 0, "en-US", true},
+		{"region only", -1, 53, "zh-Hant-TW", true},
+		{"region with script", -1, 81, "ga-Latg-IE", true},
+		{"deprecated region", -1, 70, "de-1996", true},
+		{"language only", 33, -1, "zh-Hans", true},
+		{"deprecated language", 53, -1, "ro", true},
+		{"unknown region", 11, 38, "ja", true},
+		{"unknown", 95, 1000, "", false},
+		{"none", -1, -1, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := CanonicalizeMacCodes(tt.lang, tt.region)
+			if s != tt.expect || ok != tt.ok {
+				t.Errorf("expect %q, %v, got %q, %v", tt.expect, tt.ok, s, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package locale
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"", ""},
+		{"C", "C"},
+		{"en", "en"},
+		{"en_US", "en-US"},
+		{"sr_Latn_RS", "sr-Latn-RS"},
+
+		// Old Apple names and codes.
+		{"English", "en"},
+		{"Brazilian Portugese", "pt-BR"},
+		{"Chinese, Traditional", "zh-Hant"},
+		{"en_??", "en-001"},
+		{"zh.Ha-T_TW", "zh-Hant-TW"},
+		{"ga.Lg_IE", "ga-Latg-IE"},
+		{"no-NO_NO", "nb-NO-NO"},
+
+		// ISO 639-2 and deprecated codes.
+		{"ger", "de"},
+		{"deu_DE", "de-DE"},
+		{"FRE-ca", "fr-ca"},
+		{"iw_IL", "he-IL"},
+		{"in", "id"},
+		{"no_NO", "nb-NO"},
+		{"sh_BA", "sr-Latn-BA"},
+		{"tl", "fil"},
+		{"nor", "nb"},
+
+		// Grandfathered tags.
+		{"i-klingon", "tlh"},
+		{"zh-min-nan", "nan"},
+		{"zh-min-nan-TW", "nan-TW"},
+		{"no-nyn", "nn"},
+
+		// Chinese script upgrades.
+		{"zh_TW", "zh-Hant-TW"},
+		{"zh_HK", "zh-Hant-HK"},
+		{"zh-mo", "zh-Hant-mo"},
+		{"zh_CN", "zh-Hans-CN"},
+		{"zh_SG", "zh-Hans-SG"},
+		{"zh-Hant_TW", "zh-Hant-TW"},
+		{"zh-Hans", "zh-Hans"},
+		{"zh_US", "zh-US"},
+
+		// ICU keywords.
+		{"en_US@rg=gbzzzz", "en-US-u-rg-gbzzzz"},
+		{"zh_TW@calendar=roc", "zh-Hant-TW-u-ca-roc"},
+		{"English@measure=metric", "en-u-ms-metric"},
+		{"en_US@unknown=value", "en-US"},
+
+		// POSIX names.
+		{"de_DE.UTF-8", "de-DE"},
+		{"zh_TW.Big5", "zh-Hant-TW"},
+		{"sr_RS.UTF-8@latin", "sr-Latn-RS"},
+		{"sr_RS@cyrillic", "sr-Cyrl-RS"},
+		{"ca_ES@valencia", "ca-ES-valencia"},
+		{"de_DE@euro", "de-DE-u-cu-eur"},
+		{"iw_IL.UTF-8", "he-IL"},
+		{"en_US.UTF-8@rg=gbzzzz", "en-US-u-rg-gbzzzz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := Canonicalize(tt.input)
+			if got != tt.expect {
+				t.Errorf("Canonicalize() = %v, want %v", got, tt.expect)
+			}
+			if got := Canonicalize(got); got != tt.expect {
+				t.Errorf("Canonicalize() is not idempotent, got %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestMakeTag(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect language.Tag
+	}{
+		{"English", language.English},
+		{"zh_TW", language.MustParse("zh-Hant-TW")},
+		{"iw_IL", language.MustParse("he-IL")},
+		{"en_US@rg=gbzzzz", language.MustParse("en-US-u-rg-gbzzzz")},
+		{"C", language.Und},
+		{"de_DE.UTF-8", language.MustParse("de-DE")},
+		{"sr_RS.UTF-8@latin", language.MustParse("sr-Latn-RS")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := makeTag(tt.input); got != tt.expect {
+				t.Errorf("makeTag() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
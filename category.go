@@ -0,0 +1,86 @@
+package locale
+
+import (
+	"errors"
+
+	"golang.org/x/text/language"
+)
+
+// Category is the POSIX locale category which decides the locale used
+// for a specific purpose, like formatting time or money.
+//
+// ref: https://pubs.opengroup.org/onlinepubs/9699919799/basedefs/V1_chap07.html
+type Category string
+
+// All POSIX and glibc locale categories.
+const (
+	CategoryCType          Category = "LC_CTYPE"
+	CategoryNumeric        Category = "LC_NUMERIC"
+	CategoryTime           Category = "LC_TIME"
+	CategoryCollate        Category = "LC_COLLATE"
+	CategoryMonetary       Category = "LC_MONETARY"
+	CategoryMessages       Category = "LC_MESSAGES"
+	CategoryPaper          Category = "LC_PAPER"
+	CategoryName           Category = "LC_NAME"
+	CategoryAddress        Category = "LC_ADDRESS"
+	CategoryTelephone      Category = "LC_TELEPHONE"
+	CategoryMeasurement    Category = "LC_MEASUREMENT"
+	CategoryIdentification Category = "LC_IDENTIFICATION"
+)
+
+// envs returns the env to be checked for this category in order.
+//
+// LC_ALL will overwrite all LC_* options, and LANG is the default locale.
+func (c Category) envs() []string {
+	return []string{"LC_ALL", string(c), "LANG"}
+}
+
+// DetectCategory will detect the language of category.
+//
+// The POSIX precedence LC_ALL > LC_<category> > LANG is honored in every
+// source, for example:
+//
+//	LC_ALL= LC_TIME=de_DE.UTF-8 LANG=en_US.UTF-8
+//
+// will detect "de-DE" for CategoryTime and "en-US" for CategoryNumeric.
+//
+// Registered detectors implementing CategoryDetector are tried in order,
+// others are skipped.
+func DetectCategory(cat Category) (tag language.Tag, err error) {
+	return defaultConfig.DetectCategory(cat)
+}
+
+// DetectCategory will detect the language of category with c.Detectors.
+func (c *Config) DetectCategory(cat Category) (tag language.Tag, err error) {
+	for _, d := range c.detectors() {
+		value, _, err := c.detectCategory(d, cat)
+		if err != nil && errors.Is(err, ErrNotDetected) {
+			continue
+		}
+		if err != nil {
+			return language.Und, err
+		}
+		return makeTag(c.parseEnvLc(value)), nil
+	}
+	return language.Und, &Error{"detect category", ErrNotDetected}
+}
+
+// mapLookup returns a lookup func of m.
+func mapLookup(m map[string]string) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := m[key]
+		return v, ok
+	}
+}
+
+// lookupCategory returns the first non-empty value for category via lookup
+// in the order of c.envs.
+func lookupCategory(lookup func(key string) (string, bool), c Category) (key, value string, ok bool) {
+	for _, v := range c.envs() {
+		s, ok := lookup(v)
+		if ok && s != "" {
+			return v, s, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,139 @@
+package locale
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestDetectCategory(t *testing.T) {
+	detectors = []Detector{EnvLanguageDetector, EnvLcDetector}
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		category    Category
+		expectLang  language.Tag
+		expectError error
+	}{
+		{
+			"LC_ALL wins",
+			map[string]string{"LC_ALL": "de_DE.UTF-8", "LC_TIME": "fr_FR.UTF-8", "LANG": "en_US.UTF-8"},
+			CategoryTime, language.MustParse("de-DE"), nil,
+		},
+		{
+			"category overrides LANG",
+			map[string]string{"LC_TIME": "fr_FR.UTF-8", "LANG": "en_US.UTF-8"},
+			CategoryTime, language.MustParse("fr-FR"), nil,
+		},
+		{
+			"other category ignored",
+			map[string]string{"LC_TIME": "fr_FR.UTF-8", "LANG": "en_US.UTF-8"},
+			CategoryMonetary, language.AmericanEnglish, nil,
+		},
+		{
+			"empty LC_ALL ignored",
+			map[string]string{"LC_ALL": "", "LC_NUMERIC": "ja_JP.UTF-8"},
+			CategoryNumeric, language.MustParse("ja-JP"), nil,
+		},
+		{
+			"LANGUAGE ignored",
+			map[string]string{"LANGUAGE": "zh_CN", "LANG": "en_US.UTF-8"},
+			CategoryPaper, language.AmericanEnglish, nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupEnv()
+			defer setupEnv()
+
+			for k, v := range tt.env {
+				err := os.Setenv(k, v)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			lang, err := DetectCategory(tt.category)
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("DetectCategory() error = %v, expectError %v", err, tt.expectError)
+			}
+			if lang != tt.expectLang {
+				t.Errorf("DetectCategory() = %v, want %v", lang, tt.expectLang)
+			}
+		})
+	}
+}
+
+type mockCategoryDetector struct {
+	Detector
+	value string
+}
+
+func (d mockCategoryDetector) DetectCategory(Category) (string, string, error) {
+	return d.value, "mock", nil
+}
+
+func TestConfigDetectCategoryDetectors(t *testing.T) {
+	t.Parallel()
+
+	mock := mockCategoryDetector{NewDetector("mock", nil), "de_DE.UTF-8"}
+	tests := []struct {
+		name        string
+		env         map[string]string
+		detectors   []Detector
+		expectLang  language.Tag
+		expectError error
+	}{
+		{
+			"only category detectors",
+			map[string]string{"LANGUAGE": "ja"},
+			[]Detector{EnvLanguageDetector, NewDetector("plain", func() ([]string, error) { return []string{"fr"}, nil })},
+			language.Und,
+			ErrNotDetected,
+		},
+		{
+			"custom category detector",
+			map[string]string{},
+			[]Detector{EnvLcDetector, mock},
+			language.MustParse("de-DE"),
+			nil,
+		},
+		{
+			"in order",
+			map[string]string{"LANG": "fr_FR.UTF-8"},
+			[]Detector{EnvLcDetector, mock},
+			language.MustParse("fr-FR"),
+			nil,
+		},
+		{
+			"env only",
+			map[string]string{},
+			[]Detector{EnvLcDetector},
+			language.Und,
+			ErrNotDetected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{LookupEnv: mapLookup(tt.env), Detectors: tt.detectors}
+			lang, err := c.DetectCategory(CategoryTime)
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("DetectCategory() error = %v, expectError %v", err, tt.expectError)
+			}
+			if lang != tt.expectLang {
+				t.Errorf("DetectCategory() = %v, want %v", lang, tt.expectLang)
+			}
+		})
+	}
+
+	c := &Config{LookupEnv: mapLookup(map[string]string{}), Detectors: []Detector{mock}}
+	name, _, err := c.DetectEncoding()
+	if err != nil || name != "UTF-8" {
+		t.Errorf("DetectEncoding() = %v, %v, want UTF-8", name, err)
+	}
+}
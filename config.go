@@ -0,0 +1,105 @@
+package locale
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// Config decides where detection reads env, files and command outputs from.
+//
+// The zero value reads from current process, and it's what package level
+// functions like Detect use. Replacing them makes it possible to evaluate
+// detection against a synthetic environment:
+//
+//	c := &locale.Config{
+//		LookupEnv: func(key string) (string, bool) {
+//			v, ok := map[string]string{"LANG": "de_DE.UTF-8"}[key]
+//			return v, ok
+//		},
+//		FS: fstest.MapFS{},
+//	}
+//	tag, err := c.Detect()
+//
+// Custom detectors created by NewDetector will not be affected by Config.
+type Config struct {
+	// LookupEnv is used to lookup env, os.LookupEnv will be used if nil.
+	LookupEnv func(key string) (string, bool)
+	// FS is the file system rooted at "/", absolute paths like
+	// "/etc/locale.conf" will be opened as "etc/locale.conf".
+	//
+	// The OS file system will be used if nil.
+	FS fs.FS
+	// Command is used to run a command and return its stdout, exec.Command
+	// will be used if nil.
+	Command func(name string, args ...string) ([]byte, error)
+	// Detectors are the detectors to try in order, registered detectors
+	// will be used if nil.
+	//
+	// DetectCategory and DetectEncoding only try the ones implementing
+	// CategoryDetector.
+	Detectors []Detector
+}
+
+// defaultConfig is the Config used by package level functions.
+var defaultConfig = &Config{}
+
+func (c *Config) detectors() []Detector {
+	if c.Detectors == nil {
+		return Detectors()
+	}
+	return c.Detectors
+}
+
+func (c *Config) lookupEnv(key string) (string, bool) {
+	if c.LookupEnv == nil {
+		return os.LookupEnv(key)
+	}
+	return c.LookupEnv(key)
+}
+
+func (c *Config) open(name string) (fs.File, error) {
+	if c.FS == nil {
+		return os.Open(name)
+	}
+	return c.FS.Open(fsPath(name))
+}
+
+func (c *Config) stat(name string) (fs.FileInfo, error) {
+	if c.FS == nil {
+		return os.Stat(name)
+	}
+	return fs.Stat(c.FS, fsPath(name))
+}
+
+func (c *Config) readFile(name string) ([]byte, error) {
+	if c.FS == nil {
+		return os.ReadFile(name)
+	}
+	return fs.ReadFile(c.FS, fsPath(name))
+}
+
+func (c *Config) readDir(name string) ([]fs.DirEntry, error) {
+	if c.FS == nil {
+		return os.ReadDir(name)
+	}
+	return fs.ReadDir(c.FS, fsPath(name))
+}
+
+func (c *Config) command(name string, args ...string) ([]byte, error) {
+	if c.Command == nil {
+		return exec.Command(name, args...).Output()
+	}
+	return c.Command(name, args...)
+}
+
+// fsPath converts an absolute path into the path used by fs.FS.
+func fsPath(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
@@ -0,0 +1,100 @@
+package locale
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+func TestConfigDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expectLang  language.Tag
+		expectError error
+	}{
+		{"LANGUAGE", map[string]string{"LANGUAGE": "zh_CN:en", "LANG": "en_US.UTF-8"}, language.MustParse("zh-Hans-CN"), nil},
+		{"LANG", map[string]string{"LANG": "de_DE.UTF-8"}, language.MustParse("de-DE"), nil},
+		{"C", map[string]string{"LC_ALL": "C", "LANGUAGE": "de"}, language.AmericanEnglish, nil},
+		{"alias", map[string]string{"LANG": "german"}, language.MustParse("de-DE"), nil},
+		{"legacy name", map[string]string{"LANG": "English"}, language.English, nil},
+		{"deprecated code", map[string]string{"LANG": "iw_IL.UTF-8"}, language.MustParse("he-IL"), nil},
+		{"empty", map[string]string{}, language.Und, ErrNotDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{
+				LookupEnv: mapLookup(tt.env),
+				FS:        fstest.MapFS{},
+				Detectors: []Detector{EnvLanguageDetector, EnvLcDetector},
+			}
+			lang, err := c.Detect()
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("Detect() error = %v, expectError %v", err, tt.expectError)
+			}
+			if lang != tt.expectLang {
+				t.Errorf("Detect() = %v, want %v", lang, tt.expectLang)
+			}
+		})
+	}
+}
+
+func TestConfigAlias(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"LANG": "klingon"}),
+		FS: fstest.MapFS{
+			"usr/share/locale/locale.alias": {Data: []byte("klingon tlh_US.UTF-8\n")},
+		},
+		Detectors: []Detector{EnvLcDetector},
+	}
+	res, err := c.DetectWithSource()
+	if err != nil {
+		t.Fatalf("DetectWithSource() error = %v", err)
+	}
+	if res.Raw[0] != "tlh_US" || res.Source != "LANG" {
+		t.Errorf("DetectWithSource() = %+v", res)
+	}
+}
+
+func TestConfigCommand(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		Command: func(name string, args ...string) ([]byte, error) {
+			return []byte(name + " ok\n"), nil
+		},
+	}
+	out, err := c.command("echo", "hello")
+	if err != nil {
+		t.Fatalf("command() error = %v", err)
+	}
+	if string(out) != "echo ok\n" {
+		t.Errorf("command() = %q", out)
+	}
+}
+
+func TestFSPath(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"/etc/locale.conf", "etc/locale.conf"},
+		{"etc/locale.conf", "etc/locale.conf"},
+		{"/home/user/../root/.config", "home/root/.config"},
+		{"/", "."},
+	}
+	for _, tt := range tests {
+		if got := fsPath(tt.input); got != tt.want {
+			t.Errorf("fsPath(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
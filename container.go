@@ -0,0 +1,136 @@
+package locale
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"strings"
+)
+
+// ContainerDetector detects locale via the env of container, which is
+// useful for processes exec'd into a container with a stripped env.
+//
+// Following env will be checked in order:
+//   - "/proc/1/environ" (env of PID 1 on Linux, skipped if not readable)
+//   - "process.env" in OCIConfigPath (OCI runtime config.json)
+//
+// It's not registered by default, enable it after the env detectors via:
+//
+//	locale.RegisterAfter(locale.EnvLcDetector.Name(), locale.ContainerDetector)
+var ContainerDetector = newConfigDetector("container", detectViaContainer)
+
+// OCIConfigPath is the path of OCI runtime config.json checked by
+// ContainerDetector, like "<bundle>/config.json" for OCI hooks.
+//
+// config.json is not checked if it's empty.
+var OCIConfigPath = ""
+
+// containerEnv is an env source of container, parse parses its content
+// into a map.
+type containerEnv struct {
+	path  string
+	parse func(content []byte) (map[string]string, error)
+}
+
+func getContainerEnvs() []containerEnv {
+	envs := []containerEnv{{"/proc/1/environ", func(content []byte) (map[string]string, error) {
+		return parseEnviron(content), nil
+	}}}
+	if OCIConfigPath != "" {
+		envs = append(envs, containerEnv{OCIConfigPath, parseOCIConfigEnv})
+	}
+	return envs
+}
+
+func detectViaContainer(c *Config) (_ []string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect via container", err}
+		}
+	}()
+
+	for _, e := range getContainerEnvs() {
+		vars, err := readContainerEnv(c, e)
+		if err != nil && errors.Is(err, ErrNotDetected) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		lang, _, err := detectViaVars(c, vars)
+		if err != nil && errors.Is(err, ErrNotDetected) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return lang, e.path, nil
+	}
+	return nil, "", ErrNotDetected
+}
+
+// readContainerEnv will read the env of container, ErrNotDetected will be
+// returned if it's not existing or not readable.
+func readContainerEnv(c *Config, e containerEnv) (map[string]string, error) {
+	content, err := c.readFile(e.path)
+	if err != nil && (errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission)) {
+		return nil, ErrNotDetected
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e.parse(content)
+}
+
+// parseEnviron will parse environ content into a map.
+//
+// Content should be "KEY=VALUE" pairs separated by NUL like:
+//
+//	LANG=en_US.UTF-8\x00HOME=/root\x00
+func parseEnviron(content []byte) map[string]string {
+	m := make(map[string]string)
+	for _, v := range bytes.Split(content, []byte{0}) {
+		key, value, ok := bytes.Cut(v, []byte("="))
+		if !ok {
+			continue
+		}
+		m[string(key)] = string(value)
+	}
+	return m
+}
+
+// parseOCIConfigEnv will parse "process.env" of OCI runtime config.json
+// into a map.
+//
+// Content should be like:
+//
+//	{
+//	  "ociVersion": "1.0.2",
+//	  "process": {
+//	    "env": ["PATH=/usr/bin:/bin", "LANG=C.UTF-8"]
+//	  }
+//	}
+//
+// ref: https://github.com/opencontainers/runtime-spec/blob/main/config.md#process
+func parseOCIConfigEnv(content []byte) (map[string]string, error) {
+	var config struct {
+		Process struct {
+			Env []string `json:"env"`
+		} `json:"process"`
+	}
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]string)
+	for _, v := range config.Process.Env {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok {
+			continue
+		}
+		m[key] = value
+	}
+	return m, nil
+}
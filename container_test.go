@@ -0,0 +1,95 @@
+package locale
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDetectViaContainer(t *testing.T) {
+	origin := OCIConfigPath
+	defer func() { OCIConfigPath = origin }()
+	OCIConfigPath = "/run/bundle/config.json"
+
+	config := `{
+  "ociVersion": "1.0.2",
+  "process": {
+    "env": ["PATH=/usr/bin:/bin", "LANG=ja_JP.UTF-8", "INVALID"]
+  }
+}`
+
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		expect       []string
+		expectSource string
+		expectErr    error
+	}{
+		{
+			"pid 1",
+			fstest.MapFS{
+				"proc/1/environ":         {Data: []byte("HOME=/root\x00LANG=de_DE.UTF-8\x00")},
+				"run/bundle/config.json": {Data: []byte(config)},
+			},
+			[]string{"de_DE"}, "/proc/1/environ", nil,
+		},
+		{
+			"pid 1 without locale",
+			fstest.MapFS{
+				"proc/1/environ":         {Data: []byte("HOME=/root\x00")},
+				"run/bundle/config.json": {Data: []byte(config)},
+			},
+			[]string{"ja_JP"}, "/run/bundle/config.json", nil,
+		},
+		{
+			"oci config only",
+			fstest.MapFS{"run/bundle/config.json": {Data: []byte(config)}},
+			[]string{"ja_JP"}, "/run/bundle/config.json", nil,
+		},
+		{"nothing", fstest.MapFS{}, nil, "", ErrNotDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{FS: tt.files}
+			lang, src, err := detectViaContainer(c)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("detectViaContainer() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if !reflect.DeepEqual(lang, tt.expect) {
+				t.Errorf("detectViaContainer() = %v, want %v", lang, tt.expect)
+			}
+			if src != tt.expectSource {
+				t.Errorf("detectViaContainer() source = %v, want %v", src, tt.expectSource)
+			}
+		})
+	}
+}
+
+func TestDetectViaContainerNotReadable(t *testing.T) {
+	origin := OCIConfigPath
+	defer func() { OCIConfigPath = origin }()
+	OCIConfigPath = ""
+
+	_, _, err := detectViaContainer(&Config{FS: permissionFS{}})
+	if !errors.Is(err, ErrNotDetected) {
+		t.Errorf("detectViaContainer() error = %v, want %v", err, ErrNotDetected)
+	}
+}
+
+func TestParseOCIConfigEnv(t *testing.T) {
+	_, err := parseOCIConfigEnv([]byte("{"))
+	if err == nil {
+		t.Error("parseOCIConfigEnv() expects error for invalid json")
+	}
+
+	got, err := parseOCIConfigEnv([]byte(`{"process": {"env": ["LANG=C.UTF-8", "X=a=b"]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"LANG": "C.UTF-8", "X": "a=b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseOCIConfigEnv() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,230 @@
+package locale
+
+import (
+	"sync"
+)
+
+// Detector is a source which locale could be detected from.
+type Detector interface {
+	// Name returns the name of this detector which should be unique
+	// among all registered detectors.
+	Name() string
+	// Detect returns detected languages in the order of preference.
+	//
+	// ErrNotDetected (could be wrapped) should be returned if no locale
+	// found, so that the next detector will be tried.
+	Detect() ([]string, error)
+}
+
+// SourceDetector is a Detector which could also report where the
+// detected locale comes from.
+type SourceDetector interface {
+	Detector
+	// DetectSource is the same as Detect but also returns the source of
+	// detected languages, like the env name or file path.
+	DetectSource() (langs []string, source string, err error)
+}
+
+// CategoryDetector is a Detector which could also detect the locale of a
+// specific category, it's used by DetectCategory and DetectEncoding.
+//
+// Detectors not implementing it are skipped for categories.
+type CategoryDetector interface {
+	Detector
+	// DetectCategory returns the raw value of category like "de_DE.UTF-8"
+	// and the source of it.
+	//
+	// ErrNotDetected (could be wrapped) should be returned if category is
+	// not set, so that the next detector will be tried.
+	DetectCategory(cat Category) (value string, source string, err error)
+}
+
+// NewDetector will create a Detector with given name and detect function.
+func NewDetector(name string, fn func() ([]string, error)) Detector {
+	return &detector{name: name, fn: func(*Config) ([]string, string, error) {
+		langs, err := fn()
+		return langs, "", err
+	}}
+}
+
+// NewSourceDetector will create a SourceDetector with given name and
+// detect function.
+func NewSourceDetector(name string, fn func() ([]string, string, error)) SourceDetector {
+	return &detector{name: name, fn: func(*Config) ([]string, string, error) {
+		return fn()
+	}}
+}
+
+// newConfigDetector will create a built-in detector which reads from Config.
+func newConfigDetector(name string, fn func(c *Config) ([]string, string, error)) SourceDetector {
+	return &detector{name: name, fn: fn}
+}
+
+// newConfigCategoryDetector will create a built-in detector which reads
+// from Config and could detect categories via category.
+func newConfigCategoryDetector(
+	name string,
+	fn func(c *Config) ([]string, string, error),
+	category func(c *Config, cat Category) (string, string, error),
+) CategoryDetector {
+	return &categoryDetector{detector{name: name, fn: fn}, category}
+}
+
+type detector struct {
+	name string
+	fn   func(c *Config) ([]string, string, error)
+}
+
+func (d *detector) Name() string {
+	return d.name
+}
+
+func (d *detector) Detect() ([]string, error) {
+	langs, _, err := d.fn(defaultConfig)
+	return langs, err
+}
+
+func (d *detector) DetectSource() ([]string, string, error) {
+	return d.fn(defaultConfig)
+}
+
+type categoryDetector struct {
+	detector
+	category func(c *Config, cat Category) (string, string, error)
+}
+
+func (d *categoryDetector) DetectCategory(cat Category) (string, string, error) {
+	return d.category(defaultConfig, cat)
+}
+
+// detectSource will detect via d with Config c, c will be ignored if d is
+// not created by this package.
+func (c *Config) detectSource(d Detector) ([]string, string, error) {
+	switch d := d.(type) {
+	case *detector:
+		return d.fn(c)
+	case *categoryDetector:
+		return d.fn(c)
+	case SourceDetector:
+		return d.DetectSource()
+	}
+	langs, err := d.Detect()
+	return langs, "", err
+}
+
+// detectCategory will detect category via d with Config c, c will be
+// ignored if d is not created by this package.
+//
+// ErrNotDetected will be returned if d is not a CategoryDetector.
+func (c *Config) detectCategory(d Detector, cat Category) (string, string, error) {
+	switch d := d.(type) {
+	case *categoryDetector:
+		return d.category(c, cat)
+	case CategoryDetector:
+		return d.DetectCategory(cat)
+	}
+	return "", "", ErrNotDetected
+}
+
+// detectorsLock protects detectors which is declared for every platform.
+var detectorsLock sync.RWMutex
+
+// Detectors returns all registered detectors in the order they will be tried.
+func Detectors() []Detector {
+	detectorsLock.RLock()
+	defer detectorsLock.RUnlock()
+
+	ds := make([]Detector, len(detectors))
+	copy(ds, detectors)
+	return ds
+}
+
+// SetDetectors will replace all registered detectors with ds.
+//
+// It could be used to reorder detectors:
+//
+//	locale.SetDetectors(locale.EnvLcDetector, locale.EnvLanguageDetector)
+func SetDetectors(ds ...Detector) {
+	detectorsLock.Lock()
+	defer detectorsLock.Unlock()
+
+	detectors = make([]Detector, len(ds))
+	copy(detectors, ds)
+}
+
+// Register will append d to the end of registered detectors.
+//
+// Detector with the same name will be replaced in place.
+func Register(d Detector) {
+	detectorsLock.Lock()
+	defer detectorsLock.Unlock()
+
+	if idx := indexDetector(d.Name()); idx >= 0 {
+		detectors[idx] = d
+		return
+	}
+	detectors = append(detectors, d)
+}
+
+// RegisterBefore will insert d before the detector named name.
+//
+// Detector with the same name as d will be removed first. If name is not
+// registered, d will be appended to the end.
+func RegisterBefore(name string, d Detector) {
+	registerAt(name, d, 0)
+}
+
+// RegisterAfter will insert d after the detector named name.
+//
+// Detector with the same name as d will be removed first. If name is not
+// registered, d will be appended to the end.
+func RegisterAfter(name string, d Detector) {
+	registerAt(name, d, 1)
+}
+
+func registerAt(name string, d Detector, offset int) {
+	detectorsLock.Lock()
+	defer detectorsLock.Unlock()
+
+	if idx := indexDetector(d.Name()); idx >= 0 {
+		detectors = append(detectors[:idx:idx], detectors[idx+1:]...)
+	}
+
+	idx := indexDetector(name)
+	if idx < 0 {
+		detectors = append(detectors, d)
+		return
+	}
+	idx += offset
+
+	ds := make([]Detector, 0, len(detectors)+1)
+	ds = append(ds, detectors[:idx]...)
+	ds = append(ds, d)
+	ds = append(ds, detectors[idx:]...)
+	detectors = ds
+}
+
+// Unregister will remove the detector named name, returns false if
+// no such detector registered.
+func Unregister(name string) bool {
+	detectorsLock.Lock()
+	defer detectorsLock.Unlock()
+
+	idx := indexDetector(name)
+	if idx < 0 {
+		return false
+	}
+	detectors = append(detectors[:idx:idx], detectors[idx+1:]...)
+	return true
+}
+
+// indexDetector returns the index of detector named name, caller must
+// hold detectorsLock.
+func indexDetector(name string) int {
+	for i, d := range detectors {
+		if d.Name() == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,74 @@
+package locale
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func detectorNames(ds []Detector) []string {
+	names := make([]string, 0, len(ds))
+	for _, d := range ds {
+		names = append(names, d.Name())
+	}
+	return names
+}
+
+func TestRegistry(t *testing.T) {
+	origin := Detectors()
+	defer SetDetectors(origin...)
+
+	a := NewDetector("a", func() ([]string, error) { return []string{"en"}, nil })
+	b := NewDetector("b", func() ([]string, error) { return []string{"de"}, nil })
+	c := NewDetector("c", func() ([]string, error) { return []string{"fr"}, nil })
+
+	tests := []struct {
+		name   string
+		fn     func()
+		expect []string
+	}{
+		{"set", func() { SetDetectors(a, b) }, []string{"a", "b"}},
+		{"register", func() { Register(c) }, []string{"a", "b", "c"}},
+		{"register existing", func() { Register(a) }, []string{"a", "b", "c"}},
+		{"register before", func() { RegisterBefore("a", c) }, []string{"c", "a", "b"}},
+		{"register after", func() { RegisterAfter("a", c) }, []string{"a", "c", "b"}},
+		{"register after last", func() { RegisterAfter("b", c) }, []string{"a", "b", "c"}},
+		{"register after missing", func() { RegisterAfter("x", a) }, []string{"b", "c", "a"}},
+		{"unregister", func() { Unregister("c") }, []string{"b", "a"}},
+		{"unregister missing", func() { Unregister("x") }, []string{"b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn()
+
+			if got := detectorNames(Detectors()); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("Detectors() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestRegistryDetect(t *testing.T) {
+	origin := Detectors()
+	defer SetDetectors(origin...)
+
+	SetDetectors(
+		NewDetector("skipped", func() ([]string, error) { return nil, &Error{"test", ErrNotDetected} }),
+		NewDetector("profile", func() ([]string, error) { return []string{"zh-CN"}, nil }),
+	)
+
+	lang, err := defaultConfig.detect()
+	if err != nil {
+		t.Errorf("detect() error = %v", err)
+	}
+	if !reflect.DeepEqual(lang, []string{"zh-CN"}) {
+		t.Errorf("detect() = %v, want %v", lang, []string{"zh-CN"})
+	}
+
+	SetDetectors()
+	_, err = defaultConfig.detect()
+	if !errors.Is(err, ErrNotDetected) {
+		t.Errorf("detect() error = %v, want %v", err, ErrNotDetected)
+	}
+}
@@ -0,0 +1,150 @@
+package locale
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// glibcCodesets maps normalized glibc codeset names which are not
+// registered by IANA into IANA names.
+//
+// ISO-8859-* and CP125* are handled in normalizeCodeset.
+var glibcCodesets = map[string]string{
+	"utf8":        "UTF-8",
+	"ansix341968": "US-ASCII",
+	"ascii":       "US-ASCII",
+	"eucjp":       "EUC-JP",
+	"ujis":        "EUC-JP",
+	"euckr":       "EUC-KR",
+	"euccn":       "GB2312",
+	"sjis":        "Shift_JIS",
+	"shiftjis":    "Shift_JIS",
+	"koi8r":       "KOI8-R",
+	"koi8u":       "KOI8-U",
+	"gbk":         "GBK",
+	"gb18030":     "GB18030",
+	"big5":        "Big5",
+	"big5hkscs":   "Big5-HKSCS",
+	"macintosh":   "macintosh",
+	"ibm866":      "IBM866",
+	"cp866":       "IBM866",
+	"windows31j":  "Windows-31J",
+	"hzgb2312":    "HZ-GB-2312",
+	"iso2022jp":   "ISO-2022-JP",
+	"utf16":       "UTF-16",
+	"utf16le":     "UTF-16LE",
+	"utf16be":     "UTF-16BE",
+	"gb2312":      "GB2312",
+	"latin1":      "ISO-8859-1",
+}
+
+// supersetEncodings are encodings that x/text doesn't implement, but could
+// be handled by a superset of them.
+var supersetEncodings = map[string]encoding.Encoding{
+	"GB2312": simplifiedchinese.GBK,
+}
+
+// DetectEncoding will detect the character encoding of current env.
+//
+// The codeset of the effective LC_CTYPE (LC_ALL > LC_CTYPE > LANG) is
+// normalized into an IANA name, for example "ja_JP.eucJP" returns
+// "EUC-JP" and the corresponding encoding.
+//
+// ErrNotDetected will be returned if the locale doesn't carry a codeset,
+// and ErrNotSupported will be returned if the codeset is not supported by
+// golang.org/x/text.
+func DetectEncoding() (name string, enc encoding.Encoding, err error) {
+	return defaultConfig.DetectEncoding()
+}
+
+// DetectEncoding will detect the character encoding with c.Detectors, see
+// DetectCategory for how detectors are tried.
+func (c *Config) DetectEncoding() (name string, enc encoding.Encoding, err error) {
+	for _, d := range c.detectors() {
+		value, _, err := c.detectCategory(d, CategoryCType)
+		if err != nil && errors.Is(err, ErrNotDetected) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		return parseEncoding(c.resolveAlias(value))
+	}
+	return "", nil, &Error{"detect encoding", ErrNotDetected}
+}
+
+// parseEncoding will parse the encoding from a POSIX locale name.
+func parseEncoding(s string) (string, encoding.Encoding, error) {
+	l, err := ParsePOSIX(s)
+	if err != nil {
+		return "", nil, &Error{"detect encoding", err}
+	}
+
+	codeset := l.Codeset
+	if codeset == "" {
+		// C locale uses ASCII by default.
+		if !isCLocale(l.Language) {
+			return "", nil, &Error{"detect encoding", ErrNotDetected}
+		}
+		codeset = "US-ASCII"
+	}
+	return lookupEncoding(codeset)
+}
+
+// lookupEncoding will lookup the encoding of codeset, returns the IANA
+// name and the encoding.
+func lookupEncoding(codeset string) (string, encoding.Encoding, error) {
+	name := normalizeCodeset(codeset)
+
+	if enc, ok := supersetEncodings[name]; ok {
+		return name, enc, nil
+	}
+
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil || enc == nil {
+		return "", nil, &Error{"detect encoding", fmt.Errorf("codeset %q: %w", codeset, ErrNotSupported)}
+	}
+	// Prefer the MIME name like "EUC-JP" instead of the verbose IANA name.
+	if n, err := ianaindex.MIME.Name(enc); err == nil && n != "" {
+		return n, enc, nil
+	}
+	if n, err := ianaindex.IANA.Name(enc); err == nil && n != "" {
+		return n, enc, nil
+	}
+	return name, enc, nil
+}
+
+// normalizeCodeset will convert glibc codeset like "utf8" or "iso88591"
+// into IANA name.
+//
+// glibc normalizes codeset by removing all non-alphanumeric characters
+// and converting into lower case, we do the same to find the IANA name.
+//
+// ref: https://www.gnu.org/software/libc/manual/html_node/Locale-Names.html
+func normalizeCodeset(codeset string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(codeset) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		}
+	}
+	n := b.String()
+
+	if v, ok := glibcCodesets[n]; ok {
+		return v
+	}
+	if v, ok := strings.CutPrefix(n, "iso8859"); ok && v != "" {
+		return "ISO-8859-" + v
+	}
+	for _, prefix := range []string{"cp", "windows"} {
+		if v, ok := strings.CutPrefix(n, prefix); ok && strings.HasPrefix(v, "125") {
+			return "windows-" + v
+		}
+	}
+	return codeset
+}
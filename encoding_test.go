@@ -0,0 +1,83 @@
+package locale
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestParseEncoding(t *testing.T) {
+	ascii, err := ianaindex.IANA.Encoding("US-ASCII")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input    string
+		wantName string
+		wantEnc  encoding.Encoding
+		wantErr  error
+	}{
+		{"en_US.UTF-8", "UTF-8", unicode.UTF8, nil},
+		{"en_US.utf8", "UTF-8", unicode.UTF8, nil},
+		{"ja_JP.eucJP", "EUC-JP", japanese.EUCJP, nil},
+		{"ja_JP.SJIS", "Shift_JIS", japanese.ShiftJIS, nil},
+		{"ko_KR.euckr", "EUC-KR", korean.EUCKR, nil},
+		{"ru_RU.KOI8-R", "KOI8-R", charmap.KOI8R, nil},
+		{"de_DE.iso88591", "ISO-8859-1", charmap.ISO8859_1, nil},
+		{"de_DE.ISO-8859-15@euro", "ISO-8859-15", charmap.ISO8859_15, nil},
+		{"ru_RU.CP1251", "windows-1251", charmap.Windows1251, nil},
+		{"zh_CN.GB18030", "GB18030", simplifiedchinese.GB18030, nil},
+		{"zh_CN.GB2312", "GB2312", simplifiedchinese.GBK, nil},
+		{"C", "US-ASCII", ascii, nil},
+		{"en_US", "", nil, ErrNotDetected},
+		{"en_US.unknown", "", nil, ErrNotSupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			name, enc, err := parseEncoding(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("parseEncoding() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if name != tt.wantName {
+				t.Errorf("parseEncoding() name = %v, want %v", name, tt.wantName)
+			}
+			if enc != tt.wantEnc {
+				t.Errorf("parseEncoding() enc = %v, want %v", enc, tt.wantEnc)
+			}
+		})
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	detectors = []Detector{EnvLanguageDetector, EnvLcDetector}
+	setupEnv()
+	defer setupEnv()
+
+	for k, v := range map[string]string{"LC_CTYPE": "ja_JP.eucJP", "LANG": "en_US.UTF-8"} {
+		err := os.Setenv(k, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	name, enc, err := DetectEncoding()
+	if err != nil {
+		t.Errorf("DetectEncoding() error = %v", err)
+	}
+	if name != "EUC-JP" {
+		t.Errorf("DetectEncoding() name = %v, want %v", name, "EUC-JP")
+	}
+	if enc != japanese.EUCJP {
+		t.Errorf("DetectEncoding() enc = %v, want %v", enc, japanese.EUCJP)
+	}
+}
@@ -0,0 +1,161 @@
+package locale
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// parseEnvFile will parse shell-like KEY=VALUE assignments into a map.
+//
+// The dialect is the common subset of sh and systemd's environment files,
+// which is used by locale.conf, /etc/default/locale, environment.d and so on:
+//
+//	# comment
+//	; comment
+//	LANG=en_US.UTF-8
+//	export LC_TIME='de_DE.UTF-8'   # trailing comment
+//	LC_MONETARY = "fr_FR.UTF-8"
+//	LC_PAPER=en_\
+//	GB.UTF-8
+//
+// Values could be unquoted, single quoted or double quoted, and adjacent
+// parts are concatenated like sh does. Variable expansion is not supported.
+// Malformed lines, like invalid keys or unterminated quotes, are ignored.
+// Later assignments override earlier ones, empty values are kept so that
+// they could unset the earlier ones.
+//
+// ref:
+//   - https://pubs.opengroup.org/onlinepubs/9699919799/utilities/V3_chap02.html#tag_18_02
+//   - https://www.freedesktop.org/software/systemd/man/latest/environment.d.html
+func parseEnvFile(r io.Reader) (map[string]string, error) {
+	m := make(map[string]string)
+	s := bufio.NewScanner(r)
+
+	var line string
+	for s.Scan() {
+		line += s.Text()
+		// A backslash at the end of line continues on the next line.
+		if endsWithBackslash(line) {
+			line += "\n"
+			continue
+		}
+
+		key, value, ok := parseEnvAssignment(line)
+		line = ""
+		if ok {
+			m[key] = value
+		}
+	}
+	if line != "" {
+		key, value, ok := parseEnvAssignment(line)
+		if ok {
+			m[key] = value
+		}
+	}
+	return m, s.Err()
+}
+
+// endsWithBackslash returns true if s ends with an unescaped backslash.
+func endsWithBackslash(s string) bool {
+	n := len(s) - len(strings.TrimRight(s, "\\"))
+	return n%2 == 1
+}
+
+// parseEnvAssignment will parse a single KEY=VALUE assignment, ok is false
+// if line is empty, a comment or malformed.
+func parseEnvAssignment(line string) (key, value string, ok bool) {
+	line = strings.TrimLeft(line, " \t")
+	if line == "" || line[0] == '#' || line[0] == ';' {
+		return "", "", false
+	}
+
+	if rest, found := strings.CutPrefix(line, "export"); found && rest != "" && isBlank(rest[0]) {
+		line = strings.TrimLeft(rest, " \t")
+	}
+
+	key, rest, found := strings.Cut(line, "=")
+	if !found {
+		return "", "", false
+	}
+	key = strings.TrimRight(key, " \t")
+	if !isEnvKey(key) {
+		return "", "", false
+	}
+
+	value, ok = parseEnvValue(strings.TrimLeft(rest, " \t"))
+	if !ok {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+// parseEnvValue will unquote the value part of an assignment, ok is false
+// if there is an unterminated quote.
+//
+// The value ends at the first unquoted blank, so everything after it like
+// a trailing comment will be ignored.
+func parseEnvValue(s string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case isBlank(ch):
+			return b.String(), true
+		case ch == '\\':
+			// Backslash preserves the next character, and is removed with
+			// newline together for line continuation.
+			if i+1 < len(s) {
+				i++
+				if s[i] != '\n' {
+					b.WriteByte(s[i])
+				}
+			}
+		case ch == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return "", false
+			}
+			b.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+		case ch == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				// Backslash only escapes these characters in double quotes.
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				b.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return "", false
+			}
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String(), true
+}
+
+// isEnvKey returns true if s is a valid env name like "LC_ALL".
+func isEnvKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '_', 'a' <= ch && ch <= 'z', 'A' <= ch && ch <= 'Z':
+		case '0' <= ch && ch <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isBlank(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n'
+}
@@ -0,0 +1,99 @@
+package locale
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		expect  map[string]string
+	}{
+		{"simple", "LANG=en_US.UTF-8\n", map[string]string{"LANG": "en_US.UTF-8"}},
+		{"double quoted", `LANG="en_US.UTF-8"`, map[string]string{"LANG": "en_US.UTF-8"}},
+		{"single quoted", `LANG='en_US.UTF-8'`, map[string]string{"LANG": "en_US.UTF-8"}},
+		{"export", "export LANG=en_US.UTF-8", map[string]string{"LANG": "en_US.UTF-8"}},
+		{"export as key", "export=1", map[string]string{"export": "1"}},
+		{"exported key", "exported=1", map[string]string{"exported": "1"}},
+		{"comment", "# LANG=de_DE.UTF-8\n; LC_ALL=C\nLANG=en_US.UTF-8", map[string]string{"LANG": "en_US.UTF-8"}},
+		{"trailing comment", "LANG=en_US.UTF-8 # default locale", map[string]string{"LANG": "en_US.UTF-8"}},
+		{"hash in value", "LANG=en_US#x", map[string]string{"LANG": "en_US#x"}},
+		{"spaces around equal", "  LANG = \"en_US.UTF-8\"  ", map[string]string{"LANG": "en_US.UTF-8"}},
+		{"equal in value", "LANG=en_US.UTF-8=x", map[string]string{"LANG": "en_US.UTF-8=x"}},
+		{"empty value", "LANG=en_US.UTF-8\nLC_ALL=\nLC_TIME=''", map[string]string{"LANG": "en_US.UTF-8", "LC_ALL": "", "LC_TIME": ""}},
+		{"override", "LANG=en_US.UTF-8\nLANG=de_DE.UTF-8", map[string]string{"LANG": "de_DE.UTF-8"}},
+		{"concatenated", `LANG=en_'US'."UTF-8"`, map[string]string{"LANG": "en_US.UTF-8"}},
+		{"escaped", `LANG=en\ US "LC_ALL"=x LC_TIME="a\"b\c"`, map[string]string{"LANG": "en US"}},
+		{"double quote escape", `LC_TIME="a\"b\c\\"`, map[string]string{"LC_TIME": `a"b\c\`}},
+		{"single quote literal", `LC_TIME='a\b'`, map[string]string{"LC_TIME": `a\b`}},
+		{"continuation", "LANG=en_\\\nUS.UTF-8\nLC_ALL=C", map[string]string{"LANG": "en_US.UTF-8", "LC_ALL": "C"}},
+		{"continuation in double quotes", "LANG=\"en_\\\nUS\"", map[string]string{"LANG": "en_US"}},
+		{"escaped backslash at end", "LANG=en\\\\\nLC_ALL=C", map[string]string{"LANG": "en\\", "LC_ALL": "C"}},
+		{"continuation at eof", "LANG=en_US\\", map[string]string{"LANG": "en_US"}},
+		{"crlf", "LANG=en_US.UTF-8\r\nLC_ALL=C\r\n", map[string]string{"LANG": "en_US.UTF-8", "LC_ALL": "C"}},
+		{"unterminated quote", "LANG=\"en_US.UTF-8\nLC_ALL=C", map[string]string{"LC_ALL": "C"}},
+		{"invalid key", "1LANG=x\nLA-NG=x\n=x\nLANG\nLC_ALL=C", map[string]string{"LC_ALL": "C"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEnvFile(strings.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("parseEnvFile() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("parseEnvFile() = %q, want %q", got, tt.expect)
+			}
+		})
+	}
+}
+
+func FuzzParseEnvFile(f *testing.F) {
+	for _, s := range []string{
+		"LANG=en_US.UTF-8\n",
+		"export LC_ALL='C' # comment\n",
+		"LANG = \"de_DE\\\"x\"\nLC_TIME=a\\\nb\n",
+		"LANG=\"unterminated\n",
+		"\\\\\\",
+		"'\"'\"",
+	} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, content string) {
+		m, err := parseEnvFile(strings.NewReader(content))
+		if err != nil {
+			return
+		}
+		for k := range m {
+			if !isEnvKey(k) {
+				t.Errorf("parseEnvFile(%q) returns invalid key %q", content, k)
+			}
+		}
+	})
+}
+
+func FuzzParseEnvValue(f *testing.F) {
+	for _, s := range []string{"en_US.UTF-8", "it's", "a b\\c", "\"", ""} {
+		f.Add(s)
+	}
+
+	// A single quoted value must be read back as is.
+	f.Fuzz(func(t *testing.T, value string) {
+		if strings.ContainsAny(value, "\r\n") {
+			return
+		}
+
+		content := "LANG='" + strings.ReplaceAll(value, "'", `'\''`) + "'\n"
+		m, err := parseEnvFile(strings.NewReader(content))
+		if err != nil {
+			return
+		}
+		if got, ok := m["LANG"]; !ok || got != value {
+			t.Errorf("parseEnvFile(%q) = %q, want %q", content, got, value)
+		}
+	})
+}
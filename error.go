@@ -2,6 +2,7 @@ package locale
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
@@ -9,6 +10,8 @@ var (
 	ErrNotDetected = errors.New("not detected")
 	// ErrNotSupported means current platform or language is not supported.
 	ErrNotSupported = errors.New("not supported")
+	// ErrInvalidLocale means the detected locale is not a valid language tag.
+	ErrInvalidLocale = errors.New("invalid locale")
 )
 
 // Error is the error returned by locale.
@@ -25,3 +28,89 @@ func (e *Error) Error() string {
 func (e *Error) Unwrap() error {
 	return e.Err
 }
+
+// InvalidLocaleError is the error returned while the detected locale could
+// not be parsed into a valid language.Tag.
+//
+// errors.Is(err, ErrInvalidLocale) reports true for it.
+type InvalidLocaleError struct {
+	// Value is the raw value detected.
+	Value string
+	// Detector is the name of the detector which detected the value.
+	Detector string
+	// Source is the env or file path that the value comes from.
+	Source string
+	// Err is the error returned by language.Parse, could be nil if the
+	// value is parsed as language.Und.
+	Err error
+}
+
+func (e *InvalidLocaleError) Error() string {
+	s := fmt.Sprintf("invalid locale %q", e.Value)
+	if e.Source != "" {
+		s += fmt.Sprintf(" from %s", e.Source)
+	}
+	if e.Detector != "" {
+		s += fmt.Sprintf(" via %s", e.Detector)
+	}
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+// Is reports whether target is ErrInvalidLocale.
+func (e *InvalidLocaleError) Is(target error) bool {
+	return target == ErrInvalidLocale
+}
+
+// Unwrap implements xerrors.Wrapper
+func (e *InvalidLocaleError) Unwrap() error {
+	return e.Err
+}
+
+// IgnoredError is the error returned while a value is found but ignored by
+// the rules of its source, like LANGUAGE is ignored for the C locale as
+// gettext does.
+//
+// errors.Is(err, ErrNotDetected) reports true for it, so that the next
+// detector will be tried.
+type IgnoredError struct {
+	// Value is the raw value ignored.
+	Value string
+	// Source is the env or file path that the value comes from.
+	Source string
+	// Reason is why the value is ignored, like `LC_ALL is "C"`.
+	Reason string
+}
+
+func (e *IgnoredError) Error() string {
+	return fmt.Sprintf("ignored %q from %s: %s", e.Value, e.Source, e.Reason)
+}
+
+// Is reports whether target is ErrNotDetected.
+func (e *IgnoredError) Is(target error) bool {
+	return target == ErrNotDetected
+}
+
+// PermissionError is the error returned while the environment of another
+// process is not readable by current process.
+//
+// errors.Is(err, fs.ErrPermission) reports true for it.
+type PermissionError struct {
+	// PID is the process ID.
+	PID int
+	// Path is the path which is not readable.
+	Path string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("read environment of process %d: %s: %v", e.PID, e.Path, e.Err)
+}
+
+// Unwrap implements xerrors.Wrapper
+func (e *PermissionError) Unwrap() error {
+	return e.Err
+}
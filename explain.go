@@ -0,0 +1,113 @@
+package locale
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Explanation is the report of running every registered detector.
+type Explanation struct {
+	// Attempts are the results of all detectors in the order they were tried.
+	Attempts []Attempt
+	// Winner is the index of the attempt that Detect will use, -1 means
+	// Detect will return an error.
+	Winner int
+}
+
+// Attempt is the result of a single detector.
+type Attempt struct {
+	// Detector is the name of the detector.
+	Detector string
+	// Skipped means the detector returns ErrNotDetected.
+	Skipped bool
+	// Reason is why the detector is skipped while it found a value, see
+	// IgnoredError, Raw and Source are the ignored value then.
+	Reason string
+	// Raw is the raw values detected before converting to language.Tag.
+	Raw []string
+	// Source is the env or file path that the values come from.
+	Source string
+	// Err is the error returned by the detector.
+	Err error
+	// Won means this attempt is the one that Detect will use.
+	Won bool
+}
+
+// Explain will run every registered detector in order and report what
+// each of them detected.
+//
+// Unlike Detect, Explain will not stop at the first detected locale or
+// error, so it's useful to diagnose why a locale is (or isn't) detected.
+func Explain() Explanation {
+	return defaultConfig.Explain()
+}
+
+// Explain will run every registered detector with c, see Explain for
+// details.
+func (c *Config) Explain() Explanation {
+	e := Explanation{Winner: -1}
+	decided := false
+	for _, d := range c.detectors() {
+		lang, source, err := c.detectSource(d)
+		a := Attempt{
+			Detector: d.Name(),
+			Skipped:  err != nil && errors.Is(err, ErrNotDetected),
+			Raw:      lang,
+			Source:   source,
+			Err:      err,
+		}
+		var ignored *IgnoredError
+		if a.Skipped && errors.As(err, &ignored) {
+			a.Raw = []string{ignored.Value}
+			a.Source = ignored.Source
+			a.Reason = ignored.Reason
+		}
+		// Detect will stop at the first not skipped detector, no matter
+		// it succeeds or not.
+		if !decided && !a.Skipped {
+			decided = true
+			if err == nil {
+				a.Won = true
+				e.Winner = len(e.Attempts)
+			}
+		}
+		e.Attempts = append(e.Attempts, a)
+	}
+	return e
+}
+
+// String returns a human readable report with one line per attempt.
+func (e Explanation) String() string {
+	var b strings.Builder
+	for _, a := range e.Attempts {
+		b.WriteString(a.String())
+		b.WriteByte('\n')
+	}
+	if e.Winner < 0 {
+		b.WriteString("no locale detected\n")
+	}
+	return b.String()
+}
+
+// String returns a human readable line of this attempt.
+func (a Attempt) String() string {
+	switch {
+	case a.Skipped && a.Reason == "":
+		return fmt.Sprintf("%s: skipped", a.Detector)
+	case !a.Skipped && a.Err != nil:
+		return fmt.Sprintf("%s: error: %v", a.Detector, a.Err)
+	}
+
+	s := fmt.Sprintf("%s: %s", a.Detector, strings.Join(a.Raw, ":"))
+	if a.Source != "" {
+		s += fmt.Sprintf(" (from %s)", a.Source)
+	}
+	switch {
+	case a.Won:
+		s += " [selected]"
+	case a.Skipped:
+		s += fmt.Sprintf(" [skipped: %s]", a.Reason)
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+package locale
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	testErr := errors.New("test error")
+
+	skipped := NewDetector("skipped", func() ([]string, error) {
+		return nil, &Error{"test", ErrNotDetected}
+	})
+	failed := NewDetector("failed", func() ([]string, error) {
+		return nil, testErr
+	})
+	env := NewSourceDetector("env", func() ([]string, string, error) {
+		return []string{"en_US"}, "LANG", nil
+	})
+	conf := NewSourceDetector("conf", func() ([]string, string, error) {
+		return []string{"de_DE"}, "/etc/locale.conf", nil
+	})
+
+	tests := []struct {
+		name       string
+		detectors  []Detector
+		wantWinner int
+		wantWon    []bool
+		wantString string
+	}{
+		{
+			"first detected wins",
+			[]Detector{skipped, env, conf},
+			1,
+			[]bool{false, true, false},
+			"skipped: skipped\nenv: en_US (from LANG) [selected]\nconf: de_DE (from /etc/locale.conf)\n",
+		},
+		{
+			"error stops detection",
+			[]Detector{failed, env},
+			-1,
+			[]bool{false, false},
+			"failed: error: test error\nenv: en_US (from LANG)\nno locale detected\n",
+		},
+		{
+			"nothing detected",
+			[]Detector{skipped},
+			-1,
+			[]bool{false},
+			"skipped: skipped\nno locale detected\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detectors = tt.detectors
+
+			e := Explain()
+			if e.Winner != tt.wantWinner {
+				t.Errorf("Explain() winner = %d, want %d", e.Winner, tt.wantWinner)
+			}
+			won := make([]bool, 0, len(e.Attempts))
+			for _, a := range e.Attempts {
+				won = append(won, a.Won)
+			}
+			if !reflect.DeepEqual(won, tt.wantWon) {
+				t.Errorf("Explain() won = %v, want %v", won, tt.wantWon)
+			}
+			if got := e.String(); got != tt.wantString {
+				t.Errorf("Explain() = %q, want %q", got, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestExplainIgnored(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"LC_ALL": "C", "LANGUAGE": "de"}),
+		Detectors: []Detector{EnvLanguageDetector, EnvLcDetector},
+	}
+
+	e := c.Explain()
+	a := e.Attempts[0]
+	if !a.Skipped || !reflect.DeepEqual(a.Raw, []string{"de"}) || a.Source != "LANGUAGE" || a.Reason != `LC_ALL is "C"` {
+		t.Errorf("Explain() attempt = %+v", a)
+	}
+	if !errors.Is(a.Err, ErrNotDetected) {
+		t.Errorf("Explain() attempt error = %v, want %v", a.Err, ErrNotDetected)
+	}
+
+	want := "env language: de (from LANGUAGE) [skipped: LC_ALL is \"C\"]\nenv lc: en_US (from LC_ALL) [selected]\n"
+	if got := e.String(); got != want {
+		t.Errorf("Explain() = %q, want %q", got, want)
+	}
+}
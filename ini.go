@@ -0,0 +1,54 @@
+package locale
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// parseINI will parse INI files like GLib key files and KDE config files
+// into a map of group name to keys.
+//
+// Content should be like:
+//
+//	# comment
+//	[User]
+//	Language=de_DE.UTF-8
+//	FormatsLocale=en_GB.UTF-8
+//
+// Keys before the first group belong to group "". Whitespace around keys
+// and values is trimmed, and later keys override earlier ones in the same
+// group. Values are not unquoted as neither GLib nor KDE quote values.
+//
+// KDE key flags like "LANG[$e]" are dropped, so the key will be "LANG".
+//
+// ref:
+//   - https://docs.gtk.org/glib/struct.KeyFile.html
+//   - https://api.kde.org/frameworks/kconfig/html/options.html
+func parseINI(r io.Reader) (map[string]map[string]string, error) {
+	m := make(map[string]map[string]string)
+	group := ""
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			group = line[1 : len(line)-1]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if m[group] == nil {
+			m[group] = make(map[string]string)
+		}
+		key, _, _ = strings.Cut(key, "[$")
+		m[group][strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return m, s.Err()
+}
@@ -0,0 +1,42 @@
+package locale
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseINI(t *testing.T) {
+	content := `# comment
+Version=1
+
+[User]
+Language=de_DE.UTF-8
+; comment
+FormatsLocale = en_GB.UTF-8
+XSession=gnome
+invalid line
+
+[Formats]
+LANG=fr_FR.UTF-8
+LANG=ja_JP.UTF-8
+LC_TIME[$e]=en_GB.UTF-8
+`
+	got, err := parseINI(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]string{
+		"": {"Version": "1"},
+		"User": {
+			"Language":      "de_DE.UTF-8",
+			"FormatsLocale": "en_GB.UTF-8",
+			"XSession":      "gnome",
+		},
+		"Formats": {"LANG": "ja_JP.UTF-8", "LC_TIME": "en_GB.UTF-8"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseINI() = %v, want %v", got, want)
+	}
+}
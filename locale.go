@@ -6,41 +6,128 @@ import (
 	"golang.org/x/text/language"
 )
 
+// DetectResult is the detected languages along with where they come from.
+type DetectResult struct {
+	// Tags are the detected languages in the order of preference.
+	Tags []language.Tag
+	// Detector is the name of the detector which detected the languages.
+	Detector string
+	// Raw is the raw values detected before converting to language.Tag.
+	Raw []string
+	// Source is the env or file path that the languages come from.
+	//
+	// Source could be empty if the detector doesn't report it.
+	Source string
+}
+
 // Detect will detect current env's language.
 func Detect() (tag language.Tag, err error) {
-	lang, err := detect()
+	return defaultConfig.Detect()
+}
+
+// DetectAll will detect current env's all available language.
+func DetectAll() (tags []language.Tag, err error) {
+	return defaultConfig.DetectAll()
+}
+
+// DetectStrict is the same as Detect but will not return language.Und for
+// invalid locale.
+//
+// Invalid values will be skipped and the next value or detector will be
+// tried. If no valid locale found while some values are invalid, the
+// first *InvalidLocaleError will be returned (could be checked via
+// errors.Is(err, ErrInvalidLocale)).
+func DetectStrict() (tag language.Tag, err error) {
+	return defaultConfig.DetectStrict()
+}
+
+// DetectWithSource will detect current env's all available language and
+// report which detector and source they come from.
+func DetectWithSource() (res DetectResult, err error) {
+	return defaultConfig.DetectWithSource()
+}
+
+// Detect will detect the language with c.
+func (c *Config) Detect() (tag language.Tag, err error) {
+	lang, err := c.detect()
 	if err != nil {
 		return language.Und, err
 	}
-	return language.Make(lang[0]), nil
+	return makeTag(lang[0]), nil
 }
 
-// DetectAll will detect current env's all available language.
-func DetectAll() (tags []language.Tag, err error) {
-	lang, err := detect()
+// DetectAll will detect all available language with c.
+func (c *Config) DetectAll() (tags []language.Tag, err error) {
+	lang, err := c.detect()
 	if err != nil {
 		return
 	}
+	return makeTags(lang), nil
+}
 
-	tags = make([]language.Tag, 0, len(lang))
-	for _, v := range lang {
-		tags = append(tags, language.Make(v))
+// DetectStrict will detect the language with c in strict mode, see
+// DetectStrict for details.
+func (c *Config) DetectStrict() (tag language.Tag, err error) {
+	var invalid error
+	for _, d := range c.detectors() {
+		lang, source, err := c.detectSource(d)
+		if err != nil && errors.Is(err, ErrNotDetected) {
+			continue
+		}
+		if err != nil {
+			return language.Und, err
+		}
+
+		for _, v := range lang {
+			tag, err := language.Parse(Canonicalize(v))
+			if err == nil && tag != language.Und {
+				return tag, nil
+			}
+			if invalid == nil {
+				invalid = &InvalidLocaleError{Value: v, Detector: d.Name(), Source: source, Err: err}
+			}
+		}
 	}
+	if invalid != nil {
+		return language.Und, &Error{"detect strict", invalid}
+	}
+	return language.Und, &Error{"detect strict", ErrNotDetected}
+}
+
+// DetectWithSource will detect all available language with c and report
+// which detector and source they come from.
+func (c *Config) DetectWithSource() (res DetectResult, err error) {
+	res, err = c.detectResult()
+	if err != nil {
+		return
+	}
+	res.Tags = makeTags(res.Raw)
 	return
 }
 
-type detector func() ([]string, error)
+func makeTags(lang []string) []language.Tag {
+	tags := make([]language.Tag, 0, len(lang))
+	for _, v := range lang {
+		tags = append(tags, makeTag(v))
+	}
+	return tags
+}
+
+func (c *Config) detect() (lang []string, err error) {
+	res, err := c.detectResult()
+	return res.Raw, err
+}
 
-func detect() (lang []string, err error) {
-	for _, fn := range detectors {
-		lang, err = fn()
+func (c *Config) detectResult() (res DetectResult, err error) {
+	for _, d := range c.detectors() {
+		lang, source, err := c.detectSource(d)
 		if err != nil && errors.Is(err, ErrNotDetected) {
 			continue
 		}
 		if err != nil {
-			return
+			return DetectResult{}, err
 		}
-		return
+		return DetectResult{Detector: d.Name(), Raw: lang, Source: source}, nil
 	}
-	return nil, &Error{"detect", ErrNotDetected}
+	return DetectResult{}, &Error{"detect", ErrNotDetected}
 }
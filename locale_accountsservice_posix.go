@@ -0,0 +1,170 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os/user"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// AccountsServiceUsersDir is the directory where GNOME AccountsService
+// stores the settings of users.
+var AccountsServiceUsersDir = "/var/lib/AccountsService/users"
+
+// AccountsServiceDetector detects locale via GNOME AccountsService of the
+// current user, which is what GNOME desktop actually uses.
+var AccountsServiceDetector = newConfigCategoryDetector("accounts service", detectViaAccountsService, detectCategoryViaAccountsService)
+
+// accountsServiceFormatCategories are the categories follow "FormatsLocale"
+// in GNOME session, others follow "Language".
+var accountsServiceFormatCategories = map[Category]bool{
+	CategoryNumeric:     true,
+	CategoryTime:        true,
+	CategoryMonetary:    true,
+	CategoryPaper:       true,
+	CategoryMeasurement: true,
+}
+
+// AccountsServiceLocale is the language settings of a user in GNOME
+// AccountsService.
+type AccountsServiceLocale struct {
+	// Messages are the languages of messages from "Language".
+	Messages []language.Tag
+	// Formats is the language of formats like time and numbers from
+	// "FormatsLocale", it's the same as the first of Messages if not set.
+	Formats language.Tag
+}
+
+// DetectAccountsService will read the language settings of username from
+// GNOME AccountsService.
+func DetectAccountsService(username string) (AccountsServiceLocale, error) {
+	return defaultConfig.DetectAccountsService(username)
+}
+
+// DetectAccountsService will read the language settings of username from
+// GNOME AccountsService with c.
+func (c *Config) DetectAccountsService(username string) (l AccountsServiceLocale, err error) {
+	lang, formats, _, err := readAccountsService(c, username)
+	if err != nil {
+		return AccountsServiceLocale{}, &Error{"detect accounts service", err}
+	}
+
+	if lang != "" {
+		l.Messages = makeTags(c.parseEnvLanguage(lang))
+	}
+	switch {
+	case formats != "":
+		l.Formats = makeTag(c.parseEnvLc(formats))
+	case len(l.Messages) > 0:
+		l.Formats = l.Messages[0]
+	}
+	return l, nil
+}
+
+func detectViaAccountsService(c *Config) (_ []string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect via accounts service", err}
+		}
+	}()
+
+	username, err := currentUsername(c)
+	if err != nil {
+		return nil, "", err
+	}
+	lang, _, fp, err := readAccountsService(c, username)
+	if err != nil {
+		return nil, "", err
+	}
+	if lang == "" {
+		return nil, "", ErrNotDetected
+	}
+	return c.parseEnvLanguage(lang), fp, nil
+}
+
+func detectCategoryViaAccountsService(c *Config, cat Category) (_ string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect category via accounts service", err}
+		}
+	}()
+
+	username, err := currentUsername(c)
+	if err != nil {
+		return "", "", err
+	}
+	lang, formats, fp, err := readAccountsService(c, username)
+	if err != nil {
+		return "", "", err
+	}
+	if accountsServiceFormatCategories[cat] && formats != "" {
+		return formats, fp, nil
+	}
+	if lang == "" {
+		return "", "", ErrNotDetected
+	}
+	// Language could be a list like "de_DE:en", only the first one is the
+	// locale of category.
+	lang, _, _ = strings.Cut(lang, ":")
+	return lang, fp, nil
+}
+
+// readAccountsService will read "Language" and "FormatsLocale" of username,
+// returns them with the path of file.
+//
+// Content should be like:
+//
+//	[User]
+//	Language=de_DE.UTF-8
+//	FormatsLocale=en_GB.UTF-8
+//	XSession=gnome
+func readAccountsService(c *Config, username string) (lang, formats, fp string, err error) {
+	// username must be a file name in AccountsServiceUsersDir.
+	if username == "" || username == "." || username == ".." || strings.Contains(username, "/") {
+		return "", "", "", ErrNotDetected
+	}
+
+	fp = path.Join(AccountsServiceUsersDir, username)
+	content, err := c.readFile(fp)
+	// AccountsServiceUsersDir is only readable by root on most distros.
+	if err != nil && (errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission)) {
+		return "", "", "", ErrNotDetected
+	}
+	if err != nil {
+		return "", "", "", err
+	}
+
+	m, err := parseINI(bytes.NewReader(content))
+	if err != nil {
+		return "", "", "", err
+	}
+	lang, formats = m["User"]["Language"], m["User"]["FormatsLocale"]
+	if lang == "" && formats == "" {
+		return "", "", "", ErrNotDetected
+	}
+	return lang, formats, fp, nil
+}
+
+// currentUsername returns the name of current user, env USER and LOGNAME
+// will be checked first.
+//
+// ErrNotDetected will be returned if current user is unknown.
+func currentUsername(c *Config) (string, error) {
+	for _, k := range []string{"USER", "LOGNAME"} {
+		if s, ok := c.lookupEnv(k); ok && s != "" {
+			return s, nil
+		}
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		return "", ErrNotDetected
+	}
+	return u.Username, nil
+}
@@ -0,0 +1,158 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+func TestDetectAccountsService(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		username    string
+		expect      AccountsServiceLocale
+		expectError error
+	}{
+		{
+			"alice",
+			AccountsServiceLocale{
+				Messages: []language.Tag{language.MustParse("de-DE")},
+				Formats:  language.BritishEnglish,
+			},
+			nil,
+		},
+		{
+			"bob",
+			AccountsServiceLocale{
+				Messages: []language.Tag{language.MustParse("fr-FR"), language.English},
+				Formats:  language.MustParse("fr-FR"),
+			},
+			nil,
+		},
+		{"carol", AccountsServiceLocale{}, ErrNotDetected},
+		{"dave", AccountsServiceLocale{Formats: language.MustParse("ja-JP")}, nil},
+		{"erin", AccountsServiceLocale{}, ErrNotDetected},
+		{"..", AccountsServiceLocale{}, ErrNotDetected},
+		{"../users/alice", AccountsServiceLocale{}, ErrNotDetected},
+	}
+
+	c := &Config{FS: os.DirFS("testdata")}
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := c.DetectAccountsService(tt.username)
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("DetectAccountsService() error = %v, expectError %v", err, tt.expectError)
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("DetectAccountsService() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestDetectViaAccountsService(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"USER": "bob"}),
+		FS:        os.DirFS("testdata"),
+	}
+
+	lang, src, err := detectViaAccountsService(c)
+	if err != nil {
+		t.Fatalf("detectViaAccountsService() error = %v", err)
+	}
+	if !reflect.DeepEqual(lang, []string{"fr_FR", "en"}) {
+		t.Errorf("detectViaAccountsService() = %v, want %v", lang, []string{"fr_FR", "en"})
+	}
+	if src != "/var/lib/AccountsService/users/bob" {
+		t.Errorf("detectViaAccountsService() source = %v", src)
+	}
+
+	c.LookupEnv = mapLookup(map[string]string{"LOGNAME": "dave"})
+	_, _, err = detectViaAccountsService(c)
+	if !errors.Is(err, ErrNotDetected) {
+		t.Errorf("detectViaAccountsService() error = %v, want %v", err, ErrNotDetected)
+	}
+}
+
+func TestDetectViaAccountsServicePermission(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"USER": "alice"}),
+		FS: deniedFS{
+			FS: fstest.MapFS{
+				"var/lib/AccountsService/users/alice": {Data: []byte("[User]\nLanguage=de_DE.UTF-8\n")},
+				"etc/locale.conf":                     {Data: []byte("LANG=fr_FR.UTF-8\n")},
+			},
+			denied: []string{"var/lib/AccountsService/users"},
+		},
+		Detectors: []Detector{AccountsServiceDetector, LocaleConfDetector},
+	}
+
+	_, _, err := detectViaAccountsService(c)
+	if !errors.Is(err, ErrNotDetected) {
+		t.Errorf("detectViaAccountsService() error = %v, want %v", err, ErrNotDetected)
+	}
+	_, _, err = detectCategoryViaAccountsService(c, CategoryTime)
+	if !errors.Is(err, ErrNotDetected) {
+		t.Errorf("detectCategoryViaAccountsService() error = %v, want %v", err, ErrNotDetected)
+	}
+
+	// Detection should fall back to locale.conf.
+	tag, err := c.Detect()
+	if err != nil || tag != language.MustParse("fr-FR") {
+		t.Errorf("Detect() = %v, %v, want fr-FR", tag, err)
+	}
+	tag, err = c.DetectCategory(CategoryTime)
+	if err != nil || tag != language.MustParse("fr-FR") {
+		t.Errorf("DetectCategory() = %v, %v, want fr-FR", tag, err)
+	}
+}
+
+func TestDetectCategoryViaAccountsService(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		username    string
+		category    Category
+		expect      string
+		expectError error
+	}{
+		{"alice", CategoryTime, "en_GB.UTF-8", nil},
+		{"alice", CategoryMeasurement, "en_GB.UTF-8", nil},
+		{"alice", CategoryMessages, "de_DE.UTF-8", nil},
+		{"alice", CategoryCollate, "de_DE.UTF-8", nil},
+		{"bob", CategoryTime, "fr_FR.UTF-8", nil},
+		{"dave", CategoryNumeric, "ja_JP.UTF-8", nil},
+		{"dave", CategoryMessages, "", ErrNotDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.username+"/"+string(tt.category), func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{
+				LookupEnv: mapLookup(map[string]string{"USER": tt.username}),
+				FS:        os.DirFS("testdata"),
+			}
+			got, _, err := detectCategoryViaAccountsService(c, tt.category)
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("detectCategoryViaAccountsService() error = %v, expectError %v", err, tt.expectError)
+			}
+			if got != tt.expect {
+				t.Errorf("detectCategoryViaAccountsService() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
@@ -3,18 +3,19 @@
 package locale
 
 import (
-	"bytes"
 	"fmt"
-	"os/exec"
 	"strings"
 )
 
-var detectors = []detector{
-	detectViaEnvLanguage,
-	detectViaEnvLc,
-	detectViaGetProp,
+var detectors = []Detector{
+	EnvLanguageDetector,
+	EnvLcDetector,
+	GetPropDetector,
 }
 
+// GetPropDetector detects locale via android system properties.
+var GetPropDetector = newConfigDetector("getprop", detectViaGetProp)
+
 var androidLocaleKeys = []string{
 	"persist.sys.locale",
 	"ro.product.locale",
@@ -26,30 +27,30 @@ var androidGetPropPaths = []string{
 	"getprop",
 }
 
-func detectViaGetProp() ([]string, error) {
+func detectViaGetProp(c *Config) ([]string, string, error) {
 	for _, key := range androidLocaleKeys {
-		lang, err := getSystemProperty(key)
+		lang, err := getSystemProperty(c, key)
 		if err == nil {
-			return []string{lang}, nil
+			return []string{lang}, key, nil
 		}
 	}
-	lang, country := tryCombinedLocale()
+	lang, country := tryCombinedLocale(c)
 	if lang != "" && country != "" {
-		return []string{fmt.Sprintf("%s-%s", lang, country)}, nil
+		return []string{fmt.Sprintf("%s-%s", lang, country)}, "persist.sys.language,persist.sys.country", nil
 	}
-	lang, country = tryCombinedLocaleAlt()
+	lang, country = tryCombinedLocaleAlt(c)
 	if lang != "" && country != "" {
-		return []string{fmt.Sprintf("%s-%s", lang, country)}, nil
+		return []string{fmt.Sprintf("%s-%s", lang, country)}, "ro.product.locale.language,ro.product.locale.region", nil
 	}
-	return nil, &Error{"detect via getprop", ErrNotDetected}
+	return nil, "", &Error{"detect via getprop", ErrNotDetected}
 }
 
-func tryCombinedLocale() (string, string) {
-	lang, err := getSystemProperty("persist.sys.language")
+func tryCombinedLocale(c *Config) (string, string) {
+	lang, err := getSystemProperty(c, "persist.sys.language")
 	if err != nil {
 		return "", ""
 	}
-	country, err := getSystemProperty("persist.sys.country")
+	country, err := getSystemProperty(c, "persist.sys.country")
 	if err != nil {
 		return "", ""
 	}
@@ -59,28 +60,25 @@ func tryCombinedLocale() (string, string) {
 	return lang, country
 }
 
-func tryCombinedLocaleAlt() (string, string) {
-	lang, err := getSystemProperty("ro.product.locale.language")
+func tryCombinedLocaleAlt(c *Config) (string, string) {
+	lang, err := getSystemProperty(c, "ro.product.locale.language")
 	if err != nil {
 		return "", ""
 	}
-	country, err := getSystemProperty("ro.product.locale.region")
+	country, err := getSystemProperty(c, "ro.product.locale.region")
 	if err != nil {
 		return "", ""
 	}
 	return lang, country
 }
 
-func getSystemProperty(key string) (string, error) {
+func getSystemProperty(c *Config, key string) (string, error) {
 	for _, path := range androidGetPropPaths {
-		cmd := exec.Command(path, key)
-		var out bytes.Buffer
-		cmd.Stdout = &out
-		err := cmd.Run()
+		out, err := c.command(path, key)
 		if err != nil {
 			continue
 		}
-		content := strings.TrimSpace(out.String())
+		content := strings.TrimSpace(string(out))
 		if content == "" {
 			continue
 		}
@@ -7,7 +7,7 @@ import (
 )
 
 func TestDetectViaGetProp(t *testing.T) {
-	langs, err := detectViaGetProp()
+	langs, _, err := detectViaGetProp(defaultConfig)
 
 	t.Logf("langs: %v", langs)
 	if err != nil {
@@ -0,0 +1,18 @@
+//go:build !darwin
+
+package locale
+
+import (
+	"golang.org/x/text/language"
+)
+
+// DetectForBundle will detect the language of application bundleID, which
+// is only supported on macOS.
+func DetectForBundle(bundleID string) (tag language.Tag, err error) {
+	return defaultConfig.DetectForBundle(bundleID)
+}
+
+// DetectForBundle will detect the language of application bundleID with c.
+func (c *Config) DetectForBundle(bundleID string) (tag language.Tag, err error) {
+	return language.Und, &Error{"detect for bundle", ErrNotSupported}
+}
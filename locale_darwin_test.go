@@ -1,11 +1,17 @@
 package locale
 
 import (
+	"errors"
+	"os"
+	"reflect"
 	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
 )
 
 func TestDetectViaUserDefaultsSystem(t *testing.T) {
-	langs, err := detectViaDefaultsSystem()
+	langs, _, err := detectViaDefaultsSystem(defaultConfig)
 
 	t.Logf("langs: %v", langs)
 	if err != nil {
@@ -15,3 +21,180 @@ func TestDetectViaUserDefaultsSystem(t *testing.T) {
 		t.Error("Expected non-empty langs, got empty slice")
 	}
 }
+
+func TestDetectViaDefaultsSystemPlist(t *testing.T) {
+	binary, err := os.ReadFile("testdata/plist/GlobalPreferences.plist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		expect       []string
+		expectSource string
+	}{
+		{
+			"user locale",
+			fstest.MapFS{
+				"Users/alice/Library/Preferences/.GlobalPreferences.plist": {Data: binary},
+			},
+			[]string{"en_US@rg=gbzzzz"}, "/Users/alice/Library/Preferences/.GlobalPreferences.plist:AppleLocale",
+		},
+		{
+			"user languages",
+			fstest.MapFS{
+				"Users/alice/Library/Preferences/.GlobalPreferences.plist": {Data: []byte(
+					"<plist><dict><key>AppleLanguages</key><array><string>English</string><string>ja</string></array></dict></plist>",
+				)},
+				"Library/Preferences/.GlobalPreferences.plist": {Data: binary},
+			},
+			[]string{"English", "ja"}, "/Users/alice/Library/Preferences/.GlobalPreferences.plist:AppleLanguages",
+		},
+		{
+			"global",
+			fstest.MapFS{
+				"Users/alice/Library/Preferences/.GlobalPreferences.plist": {Data: []byte("invalid")},
+				"Library/Preferences/.GlobalPreferences.plist":             {Data: binary},
+			},
+			[]string{"en_US@rg=gbzzzz"}, "/Library/Preferences/.GlobalPreferences.plist:AppleLocale",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{
+				LookupEnv: mapLookup(map[string]string{"HOME": "/Users/alice"}),
+				FS:        tt.files,
+			}
+			langs, src, err := detectViaDefaultsSystem(c)
+			if err != nil {
+				t.Fatalf("detectViaDefaultsSystem() error = %v", err)
+			}
+			if !reflect.DeepEqual(langs, tt.expect) || src != tt.expectSource {
+				t.Errorf("detectViaDefaultsSystem() = %v, %v, want %v, %v", langs, src, tt.expect, tt.expectSource)
+			}
+		})
+	}
+
+	_, _, err = detectViaDefaultsSystem(&Config{FS: fstest.MapFS{}})
+	if !errors.Is(err, ErrNotDetected) {
+		t.Errorf("detectViaDefaultsSystem() error = %v, want %v", err, ErrNotDetected)
+	}
+}
+
+func TestDetectWithoutPreferences(t *testing.T) {
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"HOME": "/Users/alice"}),
+		FS: fstest.MapFS{
+			"Users/alice/Library/Preferences/.GlobalPreferences.plist": {Data: []byte(
+				"<plist><dict><key>AppleLocale</key><string>en_US</string>" +
+					"<key>AppleMetricUnits</key><true/><key>AppleICUForce24HourTime</key><true/></dict></plist>",
+			)},
+		},
+		Detectors: []Detector{DefaultsSystemDetector},
+	}
+
+	tag, err := c.Detect()
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if tag != language.AmericanEnglish {
+		t.Errorf("Detect() = %v, want en-US", tag)
+	}
+
+	p, err := c.DetectPreferences()
+	if err != nil {
+		t.Fatalf("DetectPreferences() error = %v", err)
+	}
+	if s := p.Locale.String(); s != "en-US-u-hc-h23-ms-metric" {
+		t.Errorf("DetectPreferences() Locale = %v", s)
+	}
+}
+
+func TestDetectPreferences(t *testing.T) {
+	binary, err := os.ReadFile("testdata/plist/GlobalPreferences.plist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"HOME": "/Users/alice"}),
+		FS: fstest.MapFS{
+			"Users/alice/Library/Preferences/.GlobalPreferences.plist": {Data: []byte(
+				"<plist><dict><key>AppleTemperatureUnit</key><string>Fahrenheit</string></dict></plist>",
+			)},
+			"Library/Preferences/.GlobalPreferences.plist": {Data: binary},
+		},
+	}
+	p, err := c.DetectPreferences()
+	if err != nil {
+		t.Fatalf("DetectPreferences() error = %v", err)
+	}
+	if p.TemperatureUnit != "fahrenheit" || p.MeasurementSystem != "metric" {
+		t.Errorf("DetectPreferences() = %+v", p)
+	}
+	if s := p.Locale.String(); s != "en-US-u-fw-mon-hc-h23-ms-metric-mu-fahrenhe-rg-gbzzzz" {
+		t.Errorf("DetectPreferences() Locale = %v", s)
+	}
+
+	_, err = (&Config{FS: fstest.MapFS{}}).DetectPreferences()
+	if !errors.Is(err, ErrNotDetected) {
+		t.Errorf("DetectPreferences() error = %v, want %v", err, ErrNotDetected)
+	}
+}
+
+func TestDetectForBundle(t *testing.T) {
+	binary, err := os.ReadFile("testdata/plist/GlobalPreferences.plist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	languages := func(langs string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(
+			"<plist><dict><key>AppleLanguages</key><array><string>" + langs + "</string></array></dict></plist>",
+		)}
+	}
+
+	files := fstest.MapFS{
+		"Users/alice/Library/Containers/com.example.sandboxed/Data/Library/Preferences/com.example.sandboxed.plist": languages("fr-FR"),
+		"Users/alice/Library/Preferences/com.example.app.plist":                                                     languages("de-DE"),
+		"Library/Preferences/com.example.system.plist":                                                              languages("Japanese"),
+		"Users/alice/Library/Preferences/com.example.nolang.plist":                                                  {Data: []byte("<plist><dict/></plist>")},
+		"Users/alice/Library/Preferences/.GlobalPreferences.plist":                                                  {Data: binary},
+	}
+
+	tests := []struct {
+		bundleID string
+		expect   string
+	}{
+		{"com.example.sandboxed", "fr-FR"},
+		{"com.example.app", "de-DE"},
+		{"com.example.system", "ja"},
+		{"com.example.nolang", "en-US-u-rg-gbzzzz"},
+		{"com.example.missing", "en-US-u-rg-gbzzzz"},
+		{"../.GlobalPreferences", "en-US-u-rg-gbzzzz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.bundleID, func(t *testing.T) {
+			c := &Config{
+				LookupEnv: mapLookup(map[string]string{"HOME": "/Users/alice"}),
+				FS:        files,
+			}
+			tag, err := c.DetectForBundle(tt.bundleID)
+			if err != nil {
+				t.Fatalf("DetectForBundle() error = %v", err)
+			}
+			if tag.String() != tt.expect {
+				t.Errorf("DetectForBundle() = %v, want %v", tag, tt.expect)
+			}
+		})
+	}
+
+	_, err = (&Config{FS: fstest.MapFS{}}).DetectForBundle("com.example.app")
+	if !errors.Is(err, ErrNotDetected) {
+		t.Errorf("DetectForBundle() error = %v, want %v", err, ErrNotDetected)
+	}
+	if want := "detect for bundle: detect via defaults system: not detected"; err == nil || err.Error() != want {
+		t.Errorf("DetectForBundle() error = %v, want %q", err, want)
+	}
+}
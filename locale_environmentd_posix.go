@@ -0,0 +1,200 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// EnvironmentDDetector detects locale via systemd environment.d, which sets
+// the env of user services and sessions.
+//
+// It's only used if the user level environment.d sets the language, so
+// that the system level ones won't override the user's locale.conf, see
+// SystemEnvironmentDDetector.
+var EnvironmentDDetector = newConfigCategoryDetector("environment.d", detectViaEnvironmentD, detectCategoryViaEnvironmentD)
+
+// SystemEnvironmentDDetector detects locale via the system level
+// environment.d dirs like "/etc/environment.d", which is tried after
+// locale.conf.
+var SystemEnvironmentDDetector = newConfigCategoryDetector("system environment.d", detectViaSystemEnvironmentD, detectCategoryViaSystemEnvironmentD)
+
+func detectViaEnvironmentD(c *Config) (_ []string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect via environment.d", err}
+		}
+	}()
+
+	files, err := getUserEnvironmentDFiles(c)
+	if err != nil {
+		return nil, "", err
+	}
+	return detectViaEnvFiles(c, files)
+}
+
+func detectCategoryViaEnvironmentD(c *Config, cat Category) (_ string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect category via environment.d", err}
+		}
+	}()
+
+	files, err := getUserEnvironmentDFiles(c)
+	if err != nil {
+		return "", "", err
+	}
+	return detectCategoryViaEnvFiles(c, files, cat)
+}
+
+func detectViaSystemEnvironmentD(c *Config) (_ []string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect via system environment.d", err}
+		}
+	}()
+
+	files, err := getSystemEnvironmentDFiles(c)
+	if err != nil {
+		return nil, "", err
+	}
+	return detectViaEnvFiles(c, files)
+}
+
+func detectCategoryViaSystemEnvironmentD(c *Config, cat Category) (_ string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect category via system environment.d", err}
+		}
+	}()
+
+	files, err := getSystemEnvironmentDFiles(c)
+	if err != nil {
+		return "", "", err
+	}
+	return detectCategoryViaEnvFiles(c, files, cat)
+}
+
+// getUserEnvironmentDFiles returns the files of all environment.d dirs if
+// the user level dir sets any locale variable, ErrNotDetected will be
+// returned if not.
+//
+// The system level files are still merged under the user level ones as
+// systemd does once the user level dir is used.
+func getUserEnvironmentDFiles(c *Config) ([]envFile, error) {
+	files, err := getEnvironmentDFiles(c, getUserEnvironmentDPaths(c))
+	if err != nil {
+		return nil, err
+	}
+	vars, _, err := readEnvFiles(c, files)
+	if err != nil {
+		return nil, err
+	}
+	if !hasLocaleVars(vars) {
+		return nil, ErrNotDetected
+	}
+	return getEnvironmentDFiles(c, getEnvironmentDPaths(c))
+}
+
+// getSystemEnvironmentDFiles returns the files of system level
+// environment.d dirs, files masked by the user level dir are excluded.
+func getSystemEnvironmentDFiles(c *Config) ([]envFile, error) {
+	files, err := getEnvironmentDFiles(c, getEnvironmentDPaths(c))
+	if err != nil {
+		return nil, err
+	}
+
+	user := getUserEnvironmentDPaths(c)
+	system := files[:0]
+	for _, f := range files {
+		if len(user) == 0 || path.Dir(f.path) != user[0] {
+			system = append(system, f)
+		}
+	}
+	return system, nil
+}
+
+// hasLocaleVars checks whether vars sets LANGUAGE, LANG or any LC_*.
+func hasLocaleVars(vars map[string]string) bool {
+	for k, v := range vars {
+		if v != "" && (k == "LANGUAGE" || k == "LANG" || strings.HasPrefix(k, "LC_")) {
+			return true
+		}
+	}
+	return false
+}
+
+// getEnvironmentDPaths returns the environment.d directories in the order
+// of priority:
+//   - "$XDG_CONFIG_HOME/environment.d" or "$HOME/.config/environment.d"
+//   - "/etc/environment.d"
+//   - "/run/environment.d"
+//   - "/usr/local/lib/environment.d"
+//   - "/usr/lib/environment.d"
+//
+// ref: https://www.freedesktop.org/software/systemd/man/latest/environment.d.html
+func getEnvironmentDPaths(c *Config) []string {
+	return append(getUserEnvironmentDPaths(c),
+		"/etc/environment.d",
+		"/run/environment.d",
+		"/usr/local/lib/environment.d",
+		"/usr/lib/environment.d",
+	)
+}
+
+// getUserEnvironmentDPaths returns the user level environment.d directory,
+// empty if neither XDG_CONFIG_HOME nor HOME is set.
+func getUserEnvironmentDPaths(c *Config) []string {
+	if xdg, ok := c.lookupEnv("XDG_CONFIG_HOME"); ok && xdg != "" {
+		return []string{path.Join(xdg, "environment.d")}
+	}
+	if home, ok := c.lookupEnv("HOME"); ok && home != "" {
+		return []string{path.Join(home, ".config", "environment.d")}
+	}
+	return nil
+}
+
+// getEnvironmentDFiles will list "*.conf" files in dirs in the order
+// systemd reads them.
+//
+// A file in an earlier dir masks the file with the same name in later dirs,
+// then all files are sorted in lexical order of their names, so that later
+// files override variables set by earlier ones. Dirs not existing or not
+// readable are skipped.
+func getEnvironmentDFiles(c *Config, dirs []string) ([]envFile, error) {
+	paths := make(map[string]string)
+	for _, dir := range dirs {
+		entries, err := c.readDir(dir)
+		if err != nil && (errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission)) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".conf") {
+				continue
+			}
+			if _, ok := paths[e.Name()]; !ok {
+				paths[e.Name()] = path.Join(dir, e.Name())
+			}
+		}
+	}
+
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := make([]envFile, 0, len(names))
+	for _, name := range names {
+		files = append(files, envFile{paths[name], readEnvFile})
+	}
+	return files, nil
+}
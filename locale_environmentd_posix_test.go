@@ -0,0 +1,218 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+func TestDetectViaEnvironmentD(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		files        fs.FS
+		expect       []string
+		expectSource string
+		expectErr    error
+	}{
+		{
+			"user dir",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"home/user/.config/environment.d/10-lang.conf": {Data: []byte("LANG=de_DE.UTF-8\n")},
+			},
+			[]string{"de_DE"}, "/home/user/.config/environment.d/10-lang.conf", nil,
+		},
+		{
+			"xdg config home",
+			map[string]string{"HOME": "/home/user", "XDG_CONFIG_HOME": "/xdg"},
+			fstest.MapFS{
+				"home/user/.config/environment.d/10-lang.conf": {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"xdg/environment.d/10-lang.conf":               {Data: []byte("LANG=fr_FR.UTF-8\n")},
+			},
+			[]string{"fr_FR"}, "/xdg/environment.d/10-lang.conf", nil,
+		},
+		{
+			"lexical order across dirs",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"home/user/.config/environment.d/10-lang.conf": {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"usr/lib/environment.d/20-lang.conf":           {Data: []byte("LANG=ja_JP.UTF-8\n")},
+			},
+			[]string{"ja_JP"}, "/usr/lib/environment.d/20-lang.conf", nil,
+		},
+		{
+			"user dir without locale",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"home/user/.config/environment.d/10-path.conf": {Data: []byte("PATH=/usr/bin\n")},
+				"etc/environment.d/10-lang.conf":               {Data: []byte("LANG=ja_JP.UTF-8\n")},
+			},
+			nil, "", ErrNotDetected,
+		},
+		{
+			"system only",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"etc/environment.d/10-lang.conf": {Data: []byte("LANG=ja_JP.UTF-8\n")},
+			},
+			nil, "", ErrNotDetected,
+		},
+		{
+			"skip unreadable dir",
+			map[string]string{"HOME": "/home/user"},
+			deniedFS{
+				FS: fstest.MapFS{
+					"home/user/.config/environment.d/10-lang.conf": {Data: []byte("LANG=de_DE.UTF-8\n")},
+				},
+				denied: []string{"home/user/.config/environment.d"},
+			},
+			nil, "", ErrNotDetected,
+		},
+		{"not exist", map[string]string{}, fstest.MapFS{}, nil, "", ErrNotDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{LookupEnv: mapLookup(tt.env), FS: tt.files}
+			lang, src, err := detectViaEnvironmentD(c)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("detectViaEnvironmentD() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if !reflect.DeepEqual(lang, tt.expect) {
+				t.Errorf("detectViaEnvironmentD() = %v, want %v", lang, tt.expect)
+			}
+			if src != tt.expectSource {
+				t.Errorf("detectViaEnvironmentD() source = %v, want %v", src, tt.expectSource)
+			}
+		})
+	}
+}
+
+func TestDetectViaSystemEnvironmentD(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		files        fs.FS
+		expect       []string
+		expectSource string
+		expectErr    error
+	}{
+		{
+			"earlier dir masks same name",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"etc/environment.d/50-lang.conf":     {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"usr/lib/environment.d/50-lang.conf": {Data: []byte("LANG=ja_JP.UTF-8\n")},
+			},
+			[]string{"de_DE"}, "/etc/environment.d/50-lang.conf", nil,
+		},
+		{
+			"user dir ignored",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"home/user/.config/environment.d/10-lang.conf": {Data: []byte("LANG=fr_FR.UTF-8\n")},
+				"etc/environment.d/20-lang.conf":               {Data: []byte("LANG=ja_JP.UTF-8\n")},
+			},
+			[]string{"ja_JP"}, "/etc/environment.d/20-lang.conf", nil,
+		},
+		{
+			"user dir masks same name",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"home/user/.config/environment.d/50-lang.conf": {Data: []byte("PATH=/usr/bin\n")},
+				"etc/environment.d/50-lang.conf":               {Data: []byte("LANG=ja_JP.UTF-8\n")},
+			},
+			nil, "", ErrNotDetected,
+		},
+		{
+			"lc precedence",
+			map[string]string{},
+			fstest.MapFS{
+				"etc/environment.d/10-lang.conf": {Data: []byte("LANG=de_DE.UTF-8\nLC_MESSAGES=fr_FR.UTF-8\n")},
+				"etc/environment.d/20-lc.conf":   {Data: []byte("LC_ALL=ko_KR.UTF-8\n")},
+			},
+			[]string{"ko_KR"}, "/etc/environment.d/20-lc.conf", nil,
+		},
+		{
+			"ignore other files",
+			map[string]string{},
+			fstest.MapFS{
+				"etc/environment.d/10-lang.conf.bak": {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"etc/environment.d/README":           {Data: []byte("LANG=de_DE.UTF-8\n")},
+			},
+			nil, "", ErrNotDetected,
+		},
+		{
+			"skip unreadable dir",
+			map[string]string{"HOME": "/home/user"},
+			deniedFS{
+				FS: fstest.MapFS{
+					"home/user/.config/environment.d/10-lang.conf": {Data: []byte("LANG=de_DE.UTF-8\n")},
+					"etc/environment.d/10-lang.conf":               {Data: []byte("LANG=ja_JP.UTF-8\n")},
+				},
+				denied: []string{"home/user/.config/environment.d"},
+			},
+			[]string{"ja_JP"}, "/etc/environment.d/10-lang.conf", nil,
+		},
+		{"not exist", map[string]string{}, fstest.MapFS{}, nil, "", ErrNotDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{LookupEnv: mapLookup(tt.env), FS: tt.files}
+			lang, src, err := detectViaSystemEnvironmentD(c)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("detectViaSystemEnvironmentD() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if !reflect.DeepEqual(lang, tt.expect) {
+				t.Errorf("detectViaSystemEnvironmentD() = %v, want %v", lang, tt.expect)
+			}
+			if src != tt.expectSource {
+				t.Errorf("detectViaSystemEnvironmentD() source = %v, want %v", src, tt.expectSource)
+			}
+		})
+	}
+}
+
+func TestConfigEnvironmentDCategory(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{}),
+		FS: fstest.MapFS{
+			"etc/environment.d/10-lang.conf": {Data: []byte("LANG=en_US.UTF-8\nLC_TIME=de_DE.UTF-8\n")},
+		},
+		Detectors: []Detector{EnvLanguageDetector, EnvLcDetector, EnvironmentDDetector, LocaleConfDetector, SystemEnvironmentDDetector},
+	}
+
+	tag, err := c.DetectCategory(CategoryTime)
+	if err != nil {
+		t.Fatalf("DetectCategory() error = %v", err)
+	}
+	if tag != language.MustParse("de-DE") {
+		t.Errorf("DetectCategory() = %v, want de-DE", tag)
+	}
+
+	res, err := c.DetectWithSource()
+	if err != nil {
+		t.Fatalf("DetectWithSource() error = %v", err)
+	}
+	if res.Detector != SystemEnvironmentDDetector.Name() || res.Tags[0] != language.AmericanEnglish {
+		t.Errorf("DetectWithSource() = %+v", res)
+	}
+}
@@ -1,6 +1,6 @@
 package locale
 
-var detectors = []detector{
-	detectViaEnvLanguage,
-	detectViaEnvLc,
+var detectors = []Detector{
+	EnvLanguageDetector,
+	EnvLcDetector,
 }
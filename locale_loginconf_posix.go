@@ -0,0 +1,312 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+// LoginConfPath is the path of BSD login class capability database.
+var LoginConfPath = "/etc/login.conf"
+
+// LoginConfDetector detects locale via BSD login class capabilities, which
+// login(1) exports as env.
+//
+// It's only registered on BSD, see loginClass for the limitation of login
+// class lookup.
+var LoginConfDetector = newConfigCategoryDetector("login conf", detectViaLoginConf, detectCategoryViaLoginConf)
+
+// maxLoginCapDepth is the max depth of "tc=" references, the same as
+// getcap(3).
+const maxLoginCapDepth = 32
+
+func detectViaLoginConf(c *Config) (_ []string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect via login conf", err}
+		}
+	}()
+
+	files, err := getLoginConfFiles(c)
+	if err != nil {
+		return nil, "", err
+	}
+	return detectViaEnvFiles(c, files)
+}
+
+func detectCategoryViaLoginConf(c *Config, cat Category) (_ string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect category via login conf", err}
+		}
+	}()
+
+	files, err := getLoginConfFiles(c)
+	if err != nil {
+		return "", "", err
+	}
+	return detectCategoryViaEnvFiles(c, files, cat)
+}
+
+// getLoginConfFiles returns the login class capability files of current
+// user, the user level one overrides the system level one:
+//   - "/etc/login.conf" (record of user's login class)
+//   - "$HOME/.login_conf" (record "me")
+//
+// ref: https://man.freebsd.org/cgi/man.cgi?query=login.conf&sektion=5
+func getLoginConfFiles(c *Config) ([]envFile, error) {
+	username, err := currentUsername(c)
+	if err != nil {
+		return nil, err
+	}
+
+	class := loginClass(c, username)
+	files := []envFile{{LoginConfPath, func(c *Config, fp string) (map[string]string, error) {
+		m, err := readLoginConf(c, fp, class)
+		// Class not defined in login.conf will use "default".
+		if len(m) == 0 && err == nil && class != "default" {
+			return readLoginConf(c, fp, "default")
+		}
+		return m, err
+	}}}
+	if home, ok := c.lookupEnv("HOME"); ok && home != "" {
+		files = append(files, envFile{path.Join(home, ".login_conf"), func(c *Config, fp string) (map[string]string, error) {
+			return readLoginConf(c, fp, "me")
+		}})
+	}
+	return files, nil
+}
+
+// loginClass returns the login class of username from "/etc/master.passwd",
+// "default" will be returned if it's not available.
+//
+// "/etc/master.passwd" is only readable by root, so that the class of
+// non-root users is always "default" and only "default" record in
+// login.conf and "me" record in "$HOME/.login_conf" apply to them.
+//
+// Content should be like:
+//
+//	# name:password:uid:gid:class:change:expire:gecos:home_dir:shell
+//	alice:*:1001:1001:russian:0:0:Alice:/home/alice:/bin/sh
+func loginClass(c *Config, username string) string {
+	content, err := c.readFile("/etc/master.passwd")
+	if err != nil {
+		return "default"
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(content))
+	for s.Scan() {
+		fields := strings.Split(s.Text(), ":")
+		if len(fields) < 5 || fields[0] != username {
+			continue
+		}
+		if fields[4] != "" {
+			return fields[4]
+		}
+		break
+	}
+	return "default"
+}
+
+// readLoginConf will read the env set by record name in login.conf fp, an
+// empty map will be returned if name is not found.
+//
+// "lang" and "charset" are exported as LANG and MM_CHARSET as
+// setclassenvironment(3) does, and "setenv" could set any env like LC_TIME.
+func readLoginConf(c *Config, fp, name string) (map[string]string, error) {
+	f, err := c.open(fp)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	db, err := parseLoginConf(f)
+	if err != nil {
+		return nil, err
+	}
+	caps, err := loginCapabilities(db, name, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]string)
+	if lang, ok := lookupLoginCap(caps, "lang"); ok && lang != "" {
+		m["LANG"] = lang
+	}
+	if charset, ok := lookupLoginCap(caps, "charset"); ok && charset != "" {
+		m["MM_CHARSET"] = charset
+	}
+	// setenv is a comma separated list like "LC_TIME=de_DE.UTF-8,LC_ALL=".
+	if setenv, ok := lookupLoginCap(caps, "setenv"); ok {
+		for _, v := range strings.Split(setenv, ",") {
+			k, v, ok := strings.Cut(strings.TrimSpace(v), "=")
+			if ok && isEnvKey(k) {
+				m[k] = v
+			}
+		}
+	}
+	return m, nil
+}
+
+// parseLoginConf will parse login.conf style capability database into
+// records keyed by all their names.
+//
+// Content should be like:
+//
+//	# comment
+//	default:\
+//		:lang=C.UTF-8:\
+//		:charset=UTF-8:
+//	german|German Users Accounts:\
+//		:lang=de_DE.UTF-8:\
+//		:tc=default:
+//
+// Records could span lines with trailing backslash, and the capabilities
+// are kept as is for lookupLoginCap.
+//
+// ref: https://man.freebsd.org/cgi/man.cgi?query=getcap&sektion=3
+func parseLoginConf(r io.Reader) (map[string][]string, error) {
+	db := make(map[string][]string)
+	add := func(record string) {
+		fields := splitLoginCap(record)
+		if len(fields) == 0 || strings.TrimSpace(fields[0]) == "" {
+			return
+		}
+		for _, name := range strings.Split(fields[0], "|") {
+			if _, ok := db[name]; !ok {
+				db[name] = fields[1:]
+			}
+		}
+	}
+
+	var record string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if record == "" {
+			if t := strings.TrimSpace(line); t == "" || t[0] == '#' {
+				continue
+			}
+		} else {
+			line = strings.TrimLeft(line, " \t")
+		}
+
+		if endsWithBackslash(line) {
+			record += line[:len(line)-1]
+			continue
+		}
+		add(record + line)
+		record = ""
+	}
+	if record != "" {
+		add(record)
+	}
+	return db, s.Err()
+}
+
+// splitLoginCap will split a record by ":" which is not escaped.
+func splitLoginCap(s string) []string {
+	var fields []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ':':
+			fields = append(fields, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(fields, s[start:])
+}
+
+// loginCapabilities returns the capabilities of record name with "tc="
+// references expanded in place.
+//
+// Empty capabilities and references to not existing records are ignored.
+func loginCapabilities(db map[string][]string, name string, depth int) ([]string, error) {
+	if depth > maxLoginCapDepth {
+		return nil, errors.New("too many tc= references")
+	}
+
+	var caps []string
+	for _, v := range db[name] {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		tc, ok := strings.CutPrefix(v, "tc=")
+		if !ok {
+			caps = append(caps, v)
+			continue
+		}
+		refs, err := loginCapabilities(db, tc, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		caps = append(caps, refs...)
+	}
+	return caps, nil
+}
+
+// lookupLoginCap returns the string capability name in caps, the first
+// occurrence wins and "name@" cancels the capability.
+func lookupLoginCap(caps []string, name string) (string, bool) {
+	for _, v := range caps {
+		if v == name+"@" {
+			return "", false
+		}
+		if s, ok := strings.CutPrefix(v, name+"="); ok {
+			return unescapeLoginCap(s), true
+		}
+	}
+	return "", false
+}
+
+// unescapeLoginCap will unescape string capability like "\E", "\072" and
+// "^X" as getcap(3) does.
+func unescapeLoginCap(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '^' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i] & 037)
+		case ch == '\\' && i+1 < len(s):
+			i++
+			switch ch = s[i]; ch {
+			case 'E', 'e':
+				b.WriteByte('\033')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				n := 0
+				for j := 0; j < 3 && i < len(s) && '0' <= s[i] && s[i] <= '7'; j++ {
+					n = n*8 + int(s[i]-'0')
+					i++
+				}
+				i--
+				b.WriteByte(byte(n))
+			default:
+				b.WriteByte(ch)
+			}
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
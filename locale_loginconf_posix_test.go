@@ -0,0 +1,175 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLoginConf(t *testing.T) {
+	content := `# comment
+default:\
+	:lang=C.UTF-8:\
+	:charset=UTF-8:\
+	:welcome=/etc/motd:
+
+german|German Users:\
+	:lang=de_DE\072x:\
+	:tc=default:
+loop:tc=loop:
+escaped:lang=\E^A\:\\:
+`
+	db, err := parseLoginConf(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := db["German Users"]; !ok {
+		t.Errorf("parseLoginConf() misses alias name, got %v", db)
+	}
+
+	caps, err := loginCapabilities(db, "german", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"lang=de_DE\\072x", "lang=C.UTF-8", "charset=UTF-8", "welcome=/etc/motd"}
+	if !reflect.DeepEqual(caps, want) {
+		t.Errorf("loginCapabilities() = %q, want %q", caps, want)
+	}
+
+	tests := []struct {
+		record string
+		name   string
+		expect string
+		ok     bool
+	}{
+		{"german", "lang", "de_DE:x", true},
+		{"german", "charset", "UTF-8", true},
+		{"german", "shell", "", false},
+		{"default", "lang", "C.UTF-8", true},
+		{"escaped", "lang", "\033\001:\\", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.record+"/"+tt.name, func(t *testing.T) {
+			caps, err := loginCapabilities(db, tt.record, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := lookupLoginCap(caps, tt.name)
+			if got != tt.expect || ok != tt.ok {
+				t.Errorf("lookupLoginCap() = %q, %v, want %q, %v", got, ok, tt.expect, tt.ok)
+			}
+		})
+	}
+
+	_, err = loginCapabilities(db, "loop", 0)
+	if err == nil {
+		t.Error("loginCapabilities() expects error for tc= loop")
+	}
+}
+
+func TestReadLoginConf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		expect map[string]string
+	}{
+		{"default", map[string]string{"LANG": "C.UTF-8", "MM_CHARSET": "UTF-8", "BLOCKSIZE": "K"}},
+		{"german", map[string]string{"LANG": "de_DE", "MM_CHARSET": "UTF-8", "LC_TIME": "en_GB.UTF-8", "MAIL": "/var/mail/$"}},
+		{"russian", map[string]string{"LANG": "ru_RU.KOI8-R", "MM_CHARSET": "KOI8-R", "BLOCKSIZE": "K"}},
+		{"nolang", map[string]string{"MM_CHARSET": "UTF-8", "BLOCKSIZE": "K"}},
+		{"unknown", map[string]string{}},
+	}
+
+	c := &Config{FS: os.DirFS("testdata")}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readLoginConf(c, LoginConfPath, tt.name)
+			if err != nil {
+				t.Fatalf("readLoginConf() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("readLoginConf() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestDetectViaLoginConf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		username     string
+		home         string
+		expect       []string
+		expectSource string
+		expectErr    error
+	}{
+		{"hans", "/home/hans", []string{"de_DE"}, "/etc/login.conf", nil},
+		{"ivan", "/home/ivan", []string{"ru_RU"}, "/etc/login.conf", nil},
+		{"nobody", "/nonexistent", nil, "", ErrNotDetected},
+		{"olga", "/home/bsd", []string{"fr_FR"}, "/home/bsd/.login_conf", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{
+				LookupEnv: mapLookup(map[string]string{"USER": tt.username, "HOME": tt.home}),
+				FS:        os.DirFS("testdata"),
+			}
+			lang, src, err := detectViaLoginConf(c)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("detectViaLoginConf() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if !reflect.DeepEqual(lang, tt.expect) {
+				t.Errorf("detectViaLoginConf() = %v, want %v", lang, tt.expect)
+			}
+			if src != tt.expectSource {
+				t.Errorf("detectViaLoginConf() source = %v, want %v", src, tt.expectSource)
+			}
+		})
+	}
+}
+
+func TestDetectCategoryViaLoginConf(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"USER": "hans", "HOME": "/home/bsd"}),
+		FS:        os.DirFS("testdata"),
+		Detectors: []Detector{EnvLcDetector, LoginConfDetector},
+	}
+
+	tests := []struct {
+		category     Category
+		expect       string
+		expectSource string
+	}{
+		{CategoryTime, "en_GB.UTF-8", "/etc/login.conf"},
+		{CategoryMonetary, "de_CH.UTF-8", "/home/bsd/.login_conf"},
+		{CategoryCType, "fr_FR.UTF-8", "/home/bsd/.login_conf"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.category), func(t *testing.T) {
+			got, src, err := detectCategoryViaLoginConf(c, tt.category)
+			if err != nil {
+				t.Fatalf("detectCategoryViaLoginConf() error = %v", err)
+			}
+			if got != tt.expect || src != tt.expectSource {
+				t.Errorf("detectCategoryViaLoginConf() = %v, %v, want %v, %v", got, src, tt.expect, tt.expectSource)
+			}
+		})
+	}
+
+	name, _, err := c.DetectEncoding()
+	if err != nil || name != "UTF-8" {
+		t.Errorf("DetectEncoding() = %v, %v, want UTF-8", name, err)
+	}
+}
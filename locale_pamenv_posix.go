@@ -0,0 +1,165 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"strings"
+)
+
+// PamEnvDetector detects locale via the user level pam_env config
+// "$HOME/.pam_environment", which sets the env of login sessions.
+//
+// It's only used if the user level config sets the language, so that the
+// system level ones won't override the user's locale.conf, see
+// SystemPamEnvDetector.
+var PamEnvDetector = newConfigCategoryDetector("pam env", detectViaPamEnv, detectCategoryViaPamEnv)
+
+// SystemPamEnvDetector detects locale via the system level pam_env config
+// "/etc/security/pam_env.conf" and "/etc/environment", which is tried
+// after locale.conf.
+var SystemPamEnvDetector = newConfigCategoryDetector("system pam env", detectViaSystemPamEnv, detectCategoryViaSystemPamEnv)
+
+// EnvironmentFilePath is the path of system wide environment file.
+const EnvironmentFilePath = "/etc/environment"
+
+func detectViaPamEnv(c *Config) (_ []string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect via pam env", err}
+		}
+	}()
+
+	files, err := getUserPamEnvFiles(c)
+	if err != nil {
+		return nil, "", err
+	}
+	return detectViaEnvFiles(c, files)
+}
+
+func detectCategoryViaPamEnv(c *Config, cat Category) (_ string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect category via pam env", err}
+		}
+	}()
+
+	files, err := getUserPamEnvFiles(c)
+	if err != nil {
+		return "", "", err
+	}
+	return detectCategoryViaEnvFiles(c, files, cat)
+}
+
+func detectViaSystemPamEnv(c *Config) (_ []string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect via system pam env", err}
+		}
+	}()
+
+	return detectViaEnvFiles(c, getSystemPamEnvFiles())
+}
+
+func detectCategoryViaSystemPamEnv(c *Config, cat Category) (_ string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect category via system pam env", err}
+		}
+	}()
+
+	return detectCategoryViaEnvFiles(c, getSystemPamEnvFiles(), cat)
+}
+
+// getUserPamEnvFiles returns all pam_env config files in the order pam_env
+// reads them if the user level one sets any locale variable,
+// ErrNotDetected will be returned if not:
+//   - "/etc/security/pam_env.conf" (system level pam_env config)
+//   - "/etc/environment" (system wide environment file)
+//   - "$HOME/.pam_environment" (user level pam_env config)
+//
+// Later files override variables set by earlier ones as pam_env does.
+func getUserPamEnvFiles(c *Config) ([]envFile, error) {
+	home, ok := c.lookupEnv("HOME")
+	if !ok || home == "" {
+		return nil, ErrNotDetected
+	}
+	user := envFile{path.Join(home, ".pam_environment"), readPamEnvFile}
+
+	vars, _, err := readEnvFiles(c, []envFile{user})
+	if err != nil {
+		return nil, err
+	}
+	if !hasLocaleVars(vars) {
+		return nil, ErrNotDetected
+	}
+	return append(getSystemPamEnvFiles(), user), nil
+}
+
+// getSystemPamEnvFiles returns the system level pam_env config files in the
+// order pam_env reads them, "/etc/environment" overrides pam_env.conf.
+func getSystemPamEnvFiles() []envFile {
+	return []envFile{
+		{"/etc/security/pam_env.conf", readPamEnvFile},
+		{EnvironmentFilePath, readEnvFile},
+	}
+}
+
+// readPamEnvFile will read the pam_env file fp into a map.
+func readPamEnvFile(c *Config, fp string) (map[string]string, error) {
+	f, err := c.open(fp)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parsePamEnv(f)
+}
+
+// parsePamEnv will parse pam_env config like ~/.pam_environment into a map.
+//
+// Content could be like:
+//
+//	LANGUAGE	DEFAULT=en_US:en
+//	LANG		DEFAULT=en_US.UTF-8 OVERRIDE=de_DE.UTF-8
+//	LC_TIME=de_DE.UTF-8
+//
+// OVERRIDE will be used if it's not empty, otherwise DEFAULT is used.
+//
+// ref: https://man7.org/linux/man-pages/man5/pam_env.conf.5.html
+func parsePamEnv(r io.Reader) (map[string]string, error) {
+	m := make(map[string]string)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		// KEY=VALUE format.
+		if strings.Contains(fields[0], "=") {
+			if key, value, ok := parseEnvAssignment(s.Text()); ok && value != "" {
+				m[key] = value
+			}
+			continue
+		}
+
+		var def, override string
+		for _, v := range fields[1:] {
+			switch {
+			case strings.HasPrefix(v, "DEFAULT="):
+				def, _ = parseEnvValue(strings.TrimPrefix(v, "DEFAULT="))
+			case strings.HasPrefix(v, "OVERRIDE="):
+				override, _ = parseEnvValue(strings.TrimPrefix(v, "OVERRIDE="))
+			}
+		}
+		if override != "" {
+			m[fields[0]] = override
+		} else if def != "" {
+			m[fields[0]] = def
+		}
+	}
+	return m, s.Err()
+}
@@ -0,0 +1,226 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+func TestParsePamEnv(t *testing.T) {
+	content := `# comment
+LANGUAGE	DEFAULT=en_US:en
+LANG		DEFAULT=en_US.UTF-8 OVERRIDE=de_DE.UTF-8
+LC_TIME		OVERRIDE= DEFAULT="fr_FR.UTF-8"
+LC_PAPER	DEFAULT=
+LC_NUMERIC=ja_JP.UTF-8
+`
+	got, err := parsePamEnv(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"LANGUAGE":   "en_US:en",
+		"LANG":       "de_DE.UTF-8",
+		"LC_TIME":    "fr_FR.UTF-8",
+		"LC_NUMERIC": "ja_JP.UTF-8",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePamEnv() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectViaPamEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		files        fstest.MapFS
+		expect       []string
+		expectSource string
+		expectErr    error
+	}{
+		{
+			"user",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"home/user/.pam_environment": {Data: []byte("LANG DEFAULT=de_DE.UTF-8\n")},
+			},
+			[]string{"de_DE"}, "/home/user/.pam_environment", nil,
+		},
+		{
+			"system only",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"etc/security/pam_env.conf": {Data: []byte("LANG DEFAULT=de_DE.UTF-8\n")},
+				"etc/environment":           {Data: []byte("LANG=fr_FR.UTF-8\n")},
+			},
+			nil, "", ErrNotDetected,
+		},
+		{
+			"user without locale",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"etc/security/pam_env.conf":  {Data: []byte("LANG DEFAULT=de_DE.UTF-8\n")},
+				"home/user/.pam_environment": {Data: []byte("EDITOR DEFAULT=vim\n")},
+			},
+			nil, "", ErrNotDetected,
+		},
+		{
+			"user overrides system",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"etc/security/pam_env.conf":  {Data: []byte("LANG DEFAULT=de_DE.UTF-8\n")},
+				"home/user/.pam_environment": {Data: []byte("LANG DEFAULT=en_US.UTF-8 OVERRIDE=fr_FR.UTF-8\n")},
+			},
+			[]string{"fr_FR"}, "/home/user/.pam_environment", nil,
+		},
+		{
+			"user overrides environment file",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"etc/environment":            {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"home/user/.pam_environment": {Data: []byte("LANG=fr_FR.UTF-8\n")},
+			},
+			[]string{"fr_FR"}, "/home/user/.pam_environment", nil,
+		},
+		{
+			"lc precedence across files",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"etc/security/pam_env.conf":  {Data: []byte("LC_ALL DEFAULT=ja_JP.UTF-8\n")},
+				"home/user/.pam_environment": {Data: []byte("LANG=fr_FR.UTF-8\n")},
+			},
+			[]string{"ja_JP"}, "/etc/security/pam_env.conf", nil,
+		},
+		{
+			"no home",
+			map[string]string{},
+			fstest.MapFS{
+				"home/user/.pam_environment": {Data: []byte("LANG=fr_FR.UTF-8\n")},
+			},
+			nil, "", ErrNotDetected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{LookupEnv: mapLookup(tt.env), FS: tt.files}
+			lang, src, err := detectViaPamEnv(c)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("detectViaPamEnv() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if !reflect.DeepEqual(lang, tt.expect) {
+				t.Errorf("detectViaPamEnv() = %v, want %v", lang, tt.expect)
+			}
+			if src != tt.expectSource {
+				t.Errorf("detectViaPamEnv() source = %v, want %v", src, tt.expectSource)
+			}
+		})
+	}
+}
+
+func TestDetectViaSystemPamEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		expect       []string
+		expectSource string
+		expectErr    error
+	}{
+		{
+			"pam_env.conf",
+			fstest.MapFS{
+				"etc/security/pam_env.conf": {Data: []byte("LANG DEFAULT=de_DE.UTF-8\n")},
+			},
+			[]string{"de_DE"}, "/etc/security/pam_env.conf", nil,
+		},
+		{
+			"environment file",
+			fstest.MapFS{
+				"etc/environment": {Data: []byte("PATH=\"/usr/local/bin:/usr/bin\"\nLANG=\"de_DE.UTF-8\"\n")},
+			},
+			[]string{"de_DE"}, EnvironmentFilePath, nil,
+		},
+		{
+			"environment file overrides pam_env.conf",
+			fstest.MapFS{
+				"etc/security/pam_env.conf": {Data: []byte("LANG DEFAULT=de_DE.UTF-8\n")},
+				"etc/environment":           {Data: []byte("LANG=fr_FR.UTF-8\n")},
+			},
+			[]string{"fr_FR"}, EnvironmentFilePath, nil,
+		},
+		{
+			"user ignored",
+			fstest.MapFS{
+				"etc/environment":            {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"home/user/.pam_environment": {Data: []byte("LANG=fr_FR.UTF-8\n")},
+			},
+			[]string{"de_DE"}, EnvironmentFilePath, nil,
+		},
+		{"not exist", fstest.MapFS{}, nil, "", ErrNotDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{LookupEnv: mapLookup(map[string]string{"HOME": "/home/user"}), FS: tt.files}
+			lang, src, err := detectViaSystemPamEnv(c)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("detectViaSystemPamEnv() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if !reflect.DeepEqual(lang, tt.expect) {
+				t.Errorf("detectViaSystemPamEnv() = %v, want %v", lang, tt.expect)
+			}
+			if src != tt.expectSource {
+				t.Errorf("detectViaSystemPamEnv() source = %v, want %v", src, tt.expectSource)
+			}
+		})
+	}
+
+	c := &Config{FS: fstest.MapFS{
+		"etc/security/pam_env.conf": {Data: []byte("LC_TIME DEFAULT=de_DE.UTF-8\n")},
+		"etc/environment":           {Data: []byte("LANG=de_DE.UTF-8\nLC_TIME=en_GB.UTF-8\n")},
+	}}
+	s, src, err := detectCategoryViaSystemPamEnv(c, CategoryTime)
+	if err != nil {
+		t.Fatalf("detectCategoryViaSystemPamEnv() error = %v", err)
+	}
+	if s != "en_GB.UTF-8" || src != EnvironmentFilePath {
+		t.Errorf("detectCategoryViaSystemPamEnv() = %v, %v, want en_GB.UTF-8, %v", s, src, EnvironmentFilePath)
+	}
+}
+
+func TestConfigPamEnvOrder(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"HOME": "/home/user"}),
+		FS: fstest.MapFS{
+			"home/user/.pam_environment": {Data: []byte("LANG DEFAULT=fr_FR.UTF-8\n")},
+			"etc/locale.conf":            {Data: []byte("LANG=en_US.UTF-8\n")},
+			"etc/environment":            {Data: []byte("LANG=de_DE.UTF-8\n")},
+		},
+		Detectors: []Detector{EnvLanguageDetector, EnvLcDetector, PamEnvDetector, LocaleConfDetector, SystemPamEnvDetector},
+	}
+
+	res, err := c.DetectWithSource()
+	if err != nil {
+		t.Fatalf("DetectWithSource() error = %v", err)
+	}
+	if res.Detector != PamEnvDetector.Name() || res.Tags[0] != language.MustParse("fr-FR") {
+		t.Errorf("DetectWithSource() = %+v", res)
+	}
+}
@@ -0,0 +1,46 @@
+package locale
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"golang.org/x/text/language"
+)
+
+// DetectForPID will detect the language of process pid via its
+// environment in /proc/<pid>/environ.
+//
+// The same LANGUAGE, LC_* and LANG rules as Detect are applied. A
+// *PermissionError will be returned if the environment is not readable,
+// which usually means the process is owned by another user.
+func DetectForPID(pid int) (tag language.Tag, err error) {
+	return defaultConfig.DetectForPID(pid)
+}
+
+// DetectForPID will detect the language of process pid with c.
+func (c *Config) DetectForPID(pid int) (tag language.Tag, err error) {
+	env, err := readEnviron(c, pid)
+	if err != nil {
+		return language.Und, err
+	}
+
+	lang, _, err := detectViaVars(c, parseEnviron(env))
+	if err != nil {
+		return language.Und, err
+	}
+	return makeTag(lang[0]), nil
+}
+
+// readEnviron will read the environment of process pid.
+func readEnviron(c *Config, pid int) ([]byte, error) {
+	fp := fmt.Sprintf("/proc/%d/environ", pid)
+	content, err := c.readFile(fp)
+	if err != nil && errors.Is(err, fs.ErrPermission) {
+		return nil, &PermissionError{PID: pid, Path: fp, Err: err}
+	}
+	if err != nil {
+		return nil, &Error{"detect for pid", err}
+	}
+	return content, nil
+}
@@ -0,0 +1,82 @@
+package locale
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+func TestDetectForPID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		environ     string
+		expectLang  language.Tag
+		expectError error
+	}{
+		{"LANG", "HOME=/root\x00LANG=de_DE.UTF-8\x00", language.MustParse("de-DE"), nil},
+		{"LANGUAGE", "LANG=de_DE.UTF-8\x00LANGUAGE=fr:en\x00", language.French, nil},
+		{"LC_ALL=C", "LC_ALL=C\x00LANGUAGE=fr\x00", language.AmericanEnglish, nil},
+		{"value contains =", "X=a=b\x00LC_MESSAGES=ja_JP.UTF-8\x00", language.MustParse("ja-JP"), nil},
+		{"empty", "HOME=/root\x00", language.Und, ErrNotDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{FS: fstest.MapFS{
+				"proc/42/environ": {Data: []byte(tt.environ)},
+			}}
+			lang, err := c.DetectForPID(42)
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("DetectForPID() error = %v, expectError %v", err, tt.expectError)
+			}
+			if lang != tt.expectLang {
+				t.Errorf("DetectForPID() = %v, want %v", lang, tt.expectLang)
+			}
+		})
+	}
+}
+
+func TestDetectForPIDPermission(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{FS: permissionFS{}}
+	_, err := c.DetectForPID(1)
+
+	var pe *PermissionError
+	if !errors.As(err, &pe) {
+		t.Fatalf("DetectForPID() error = %v, expect *PermissionError", err)
+	}
+	if pe.PID != 1 || pe.Path != "/proc/1/environ" {
+		t.Errorf("DetectForPID() error = %+v", pe)
+	}
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("DetectForPID() error = %v, expect fs.ErrPermission", err)
+	}
+}
+
+func TestDetectForPIDNotExist(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{FS: fstest.MapFS{}}
+	_, err := c.DetectForPID(42)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("DetectForPID() error = %v, expect fs.ErrNotExist", err)
+	}
+}
+
+func TestDetectForSelf(t *testing.T) {
+	_, err := DetectForPID(os.Getpid())
+
+	var pe *PermissionError
+	if errors.As(err, &pe) {
+		t.Errorf("DetectForPID() error = %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package locale
+
+import (
+	"golang.org/x/text/language"
+)
+
+// DetectForPID will detect the language of process pid via its
+// environment, which is only supported on Linux.
+func DetectForPID(pid int) (tag language.Tag, err error) {
+	return defaultConfig.DetectForPID(pid)
+}
+
+// DetectForPID will detect the language of process pid with c.
+func (c *Config) DetectForPID(pid int) (tag language.Tag, err error) {
+	return language.Und, &Error{"detect for pid", ErrNotSupported}
+}
@@ -0,0 +1,219 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// PlasmaDetector detects locale via KDE Plasma's plasma-localerc, which
+// Plasma exports as env at session startup.
+//
+// It's only used if the user level plasma-localerc sets the language, so
+// that the system level one won't override the user's locale.conf, see
+// SystemPlasmaDetector.
+var PlasmaDetector = newConfigCategoryDetector("plasma", detectViaPlasma, detectCategoryViaPlasma)
+
+// SystemPlasmaDetector detects locale via the system level plasma-localerc
+// in "$XDG_CONFIG_DIRS" (default to "/etc/xdg"), which is tried after
+// locale.conf.
+var SystemPlasmaDetector = newConfigCategoryDetector("system plasma", detectViaSystemPlasma, detectCategoryViaSystemPlasma)
+
+// PlasmaLocale is the language settings in KDE Plasma.
+type PlasmaLocale struct {
+	// Translations are the languages of translations from
+	// "[Translations] LANGUAGE".
+	Translations []language.Tag
+	// Lang is the default language of formats from "[Formats] LANG".
+	Lang language.Tag
+	// Formats are the languages of categories set explicitly like
+	// "[Formats] LC_TIME".
+	Formats map[Category]language.Tag
+}
+
+// Format returns the language of formats for category, Lang will be
+// returned if it's not set explicitly.
+func (l PlasmaLocale) Format(cat Category) language.Tag {
+	if tag, ok := l.Formats[cat]; ok {
+		return tag
+	}
+	return l.Lang
+}
+
+// DetectPlasma will read the language settings of current user in KDE
+// Plasma.
+func DetectPlasma() (PlasmaLocale, error) {
+	return defaultConfig.DetectPlasma()
+}
+
+// DetectPlasma will read the language settings of current user in KDE
+// Plasma with c.
+func (c *Config) DetectPlasma() (l PlasmaLocale, err error) {
+	vars, _, err := readEnvFiles(c, getPlasmaLocalercFiles(c))
+	if err != nil {
+		return PlasmaLocale{}, &Error{"detect plasma", err}
+	}
+	if len(vars) == 0 {
+		return PlasmaLocale{}, &Error{"detect plasma", ErrNotDetected}
+	}
+
+	for k, v := range vars {
+		if v == "" {
+			continue
+		}
+		switch {
+		case k == "LANGUAGE":
+			l.Translations = makeTags(c.parseEnvLanguage(v))
+		case k == "LANG":
+			l.Lang = makeTag(c.parseEnvLc(v))
+		case strings.HasPrefix(k, "LC_"):
+			if l.Formats == nil {
+				l.Formats = make(map[Category]language.Tag)
+			}
+			l.Formats[Category(k)] = makeTag(c.parseEnvLc(v))
+		}
+	}
+	return l, nil
+}
+
+func detectViaPlasma(c *Config) (_ []string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect via plasma", err}
+		}
+	}()
+
+	if err := checkUserPlasmaLocalerc(c); err != nil {
+		return nil, "", err
+	}
+	return detectViaEnvFiles(c, getPlasmaLocalercFiles(c))
+}
+
+func detectCategoryViaPlasma(c *Config, cat Category) (_ string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect category via plasma", err}
+		}
+	}()
+
+	if err := checkUserPlasmaLocalerc(c); err != nil {
+		return "", "", err
+	}
+	return detectCategoryViaEnvFiles(c, getPlasmaLocalercFiles(c), cat)
+}
+
+func detectViaSystemPlasma(c *Config) (_ []string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect via system plasma", err}
+		}
+	}()
+
+	return detectViaEnvFiles(c, getSystemPlasmaLocalercFiles(c))
+}
+
+func detectCategoryViaSystemPlasma(c *Config, cat Category) (_ string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect category via system plasma", err}
+		}
+	}()
+
+	return detectCategoryViaEnvFiles(c, getSystemPlasmaLocalercFiles(c), cat)
+}
+
+// checkUserPlasmaLocalerc will check whether the user level plasma-localerc
+// sets any language, ErrNotDetected will be returned if not.
+//
+// The system level ones are still merged under it as KConfig does once the
+// user level one is used.
+func checkUserPlasmaLocalerc(c *Config) error {
+	vars, _, err := readEnvFiles(c, getUserPlasmaLocalercFiles(c))
+	if err != nil {
+		return err
+	}
+	if len(vars) == 0 {
+		return ErrNotDetected
+	}
+	return nil
+}
+
+// readPlasmaLocalerc will read plasma-localerc fp into env like vars as
+// Plasma exports them.
+//
+// Content should be like:
+//
+//	[Formats]
+//	LANG=de_DE.UTF-8
+//	LC_TIME=en_GB.UTF-8
+//
+//	[Translations]
+//	LANGUAGE=de:en_US
+//
+// ref: https://invent.kde.org/plasma/plasma-workspace/-/blob/master/startkde/startplasma.cpp
+func readPlasmaLocalerc(c *Config, fp string) (map[string]string, error) {
+	f, err := c.open(fp)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := parseINI(f)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string)
+	for k, v := range m["Formats"] {
+		if k == "LANG" || strings.HasPrefix(k, "LC_") {
+			vars[k] = v
+		}
+	}
+	if v, ok := m["Translations"]["LANGUAGE"]; ok {
+		vars["LANGUAGE"] = v
+	}
+	return vars, nil
+}
+
+// getPlasmaLocalercFiles returns plasma-localerc files from the lowest
+// priority to the highest, keys in later files override the earlier ones
+// as KConfig does:
+//   - "$XDG_CONFIG_DIRS/plasma-localerc" (default to "/etc/xdg")
+//   - "$XDG_CONFIG_HOME/plasma-localerc" or "$HOME/.config/plasma-localerc"
+//
+// ref: https://specifications.freedesktop.org/basedir-spec/basedir-spec-latest.html
+func getPlasmaLocalercFiles(c *Config) []envFile {
+	return append(getSystemPlasmaLocalercFiles(c), getUserPlasmaLocalercFiles(c)...)
+}
+
+// getSystemPlasmaLocalercFiles returns the system level plasma-localerc
+// files from the lowest priority to the highest.
+func getSystemPlasmaLocalercFiles(c *Config) []envFile {
+	dirs := []string{"/etc/xdg"}
+	if s, ok := c.lookupEnv("XDG_CONFIG_DIRS"); ok && s != "" {
+		dirs = strings.Split(s, ":")
+	}
+
+	// Dirs in XDG_CONFIG_DIRS are in the order of preference.
+	var files []envFile
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if dirs[i] != "" {
+			files = append(files, envFile{path.Join(dirs[i], "plasma-localerc"), readPlasmaLocalerc})
+		}
+	}
+	return files
+}
+
+// getUserPlasmaLocalercFiles returns the user level plasma-localerc file.
+func getUserPlasmaLocalercFiles(c *Config) []envFile {
+	var files []envFile
+	if xdg, ok := c.lookupEnv("XDG_CONFIG_HOME"); ok && xdg != "" {
+		files = append(files, envFile{path.Join(xdg, "plasma-localerc"), readPlasmaLocalerc})
+	} else if home, ok := c.lookupEnv("HOME"); ok && home != "" {
+		files = append(files, envFile{path.Join(home, ".config", "plasma-localerc"), readPlasmaLocalerc})
+	}
+	return files
+}
@@ -0,0 +1,207 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+func TestDetectPlasma(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"HOME": "/home/kde"}),
+		FS:        os.DirFS("testdata"),
+	}
+
+	l, err := c.DetectPlasma()
+	if err != nil {
+		t.Fatalf("DetectPlasma() error = %v", err)
+	}
+
+	expect := PlasmaLocale{
+		Translations: []language.Tag{language.German, language.AmericanEnglish},
+		Lang:         language.MustParse("de-DE"),
+		Formats: map[Category]language.Tag{
+			CategoryMeasurement: language.BritishEnglish,
+			CategoryTime:        language.MustParse("en-DK"),
+			CategoryMonetary:    language.MustParse("fr-FR"),
+		},
+	}
+	if !reflect.DeepEqual(l, expect) {
+		t.Errorf("DetectPlasma() = %v, want %v", l, expect)
+	}
+	if tag := l.Format(CategoryNumeric); tag != language.MustParse("de-DE") {
+		t.Errorf("Format(CategoryNumeric) = %v, want de-DE", tag)
+	}
+	if tag := l.Format(CategoryTime); tag != language.MustParse("en-DK") {
+		t.Errorf("Format(CategoryTime) = %v, want en-DK", tag)
+	}
+
+	_, err = (&Config{LookupEnv: mapLookup(map[string]string{}), FS: fstest.MapFS{}}).DetectPlasma()
+	if !errors.Is(err, ErrNotDetected) {
+		t.Errorf("DetectPlasma() error = %v, want %v", err, ErrNotDetected)
+	}
+}
+
+func TestDetectViaPlasma(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		files        fstest.MapFS
+		expect       []string
+		expectSource string
+		expectErr    error
+	}{
+		{
+			"translations",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"home/user/.config/plasma-localerc": {Data: []byte("[Formats]\nLANG=en_US.UTF-8\n\n[Translations]\nLANGUAGE=fr:en_US\n")},
+			},
+			[]string{"fr", "en_US"}, "/home/user/.config/plasma-localerc", nil,
+		},
+		{
+			"formats only",
+			map[string]string{"XDG_CONFIG_HOME": "/xdg"},
+			fstest.MapFS{
+				"xdg/plasma-localerc": {Data: []byte("[Formats]\nLANG=ja_JP.UTF-8\n")},
+			},
+			[]string{"ja_JP"}, "/xdg/plasma-localerc", nil,
+		},
+		{
+			"user overrides system",
+			map[string]string{"HOME": "/home/user", "XDG_CONFIG_DIRS": "/etc/kde:/etc/xdg"},
+			fstest.MapFS{
+				"etc/xdg/plasma-localerc":           {Data: []byte("[Translations]\nLANGUAGE=ko\n")},
+				"etc/kde/plasma-localerc":           {Data: []byte("[Translations]\nLANGUAGE=zh_CN\n")},
+				"home/user/.config/plasma-localerc": {Data: []byte("[Formats]\nLANG=en_US.UTF-8\n")},
+			},
+			[]string{"zh_CN"}, "/etc/kde/plasma-localerc", nil,
+		},
+		{
+			"other groups",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"home/user/.config/plasma-localerc": {Data: []byte("[General]\nLANG=en_US.UTF-8\n")},
+			},
+			nil, "", ErrNotDetected,
+		},
+		{
+			"system only",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"etc/xdg/plasma-localerc": {Data: []byte("[Formats]\nLANG=en_US.UTF-8\n")},
+			},
+			nil, "", ErrNotDetected,
+		},
+		{"not exist", map[string]string{"HOME": "/home/user"}, fstest.MapFS{}, nil, "", ErrNotDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{LookupEnv: mapLookup(tt.env), FS: tt.files}
+			lang, src, err := detectViaPlasma(c)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("detectViaPlasma() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if !reflect.DeepEqual(lang, tt.expect) {
+				t.Errorf("detectViaPlasma() = %v, want %v", lang, tt.expect)
+			}
+			if src != tt.expectSource {
+				t.Errorf("detectViaPlasma() source = %v, want %v", src, tt.expectSource)
+			}
+		})
+	}
+}
+
+func TestDetectViaSystemPlasma(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		files        fstest.MapFS
+		expect       []string
+		expectSource string
+		expectErr    error
+	}{
+		{
+			"default dir",
+			map[string]string{"HOME": "/home/user"},
+			fstest.MapFS{
+				"etc/xdg/plasma-localerc": {Data: []byte("[Formats]\nLANG=en_US.UTF-8\n")},
+			},
+			[]string{"en_US"}, "/etc/xdg/plasma-localerc", nil,
+		},
+		{
+			"user ignored",
+			map[string]string{"HOME": "/home/user", "XDG_CONFIG_DIRS": "/etc/kde:/etc/xdg"},
+			fstest.MapFS{
+				"etc/xdg/plasma-localerc":           {Data: []byte("[Translations]\nLANGUAGE=ko\n")},
+				"etc/kde/plasma-localerc":           {Data: []byte("[Translations]\nLANGUAGE=zh_CN\n")},
+				"home/user/.config/plasma-localerc": {Data: []byte("[Translations]\nLANGUAGE=fr\n")},
+			},
+			[]string{"zh_CN"}, "/etc/kde/plasma-localerc", nil,
+		},
+		{"not exist", map[string]string{"HOME": "/home/user"}, fstest.MapFS{}, nil, "", ErrNotDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{LookupEnv: mapLookup(tt.env), FS: tt.files}
+			lang, src, err := detectViaSystemPlasma(c)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("detectViaSystemPlasma() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if !reflect.DeepEqual(lang, tt.expect) {
+				t.Errorf("detectViaSystemPlasma() = %v, want %v", lang, tt.expect)
+			}
+			if src != tt.expectSource {
+				t.Errorf("detectViaSystemPlasma() source = %v, want %v", src, tt.expectSource)
+			}
+		})
+	}
+}
+
+func TestDetectCategoryViaPlasma(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"HOME": "/home/kde"}),
+		FS:        os.DirFS("testdata"),
+	}
+
+	tests := []struct {
+		category     Category
+		expect       string
+		expectSource string
+	}{
+		{CategoryTime, "en_DK.UTF-8", "/home/kde/.config/plasma-localerc"},
+		{CategoryMonetary, "fr_FR.UTF-8", "/etc/xdg/plasma-localerc"},
+		{CategoryNumeric, "de_DE.UTF-8", "/home/kde/.config/plasma-localerc"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.category), func(t *testing.T) {
+			got, src, err := detectCategoryViaPlasma(c, tt.category)
+			if err != nil {
+				t.Fatalf("detectCategoryViaPlasma() error = %v", err)
+			}
+			if got != tt.expect || src != tt.expectSource {
+				t.Errorf("detectCategoryViaPlasma() = %v, %v, want %v, %v", got, src, tt.expect, tt.expectSource)
+			}
+		})
+	}
+}
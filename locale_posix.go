@@ -3,69 +3,186 @@
 package locale
 
 import (
-	"bufio"
-	"os"
+	"errors"
+	"io/fs"
 	"path"
-	"strings"
 )
 
-var detectors = []detector{
-	detectViaEnvLanguage,
-	detectViaEnvLc,
-	detectViaLocaleConf,
-}
+// LocaleConfDetector detects locale via locale.conf.
+var LocaleConfDetector = newConfigCategoryDetector("locale conf", detectViaLocaleConf, detectCategoryViaLocaleConf)
 
-func detectViaLocaleConf() (_ []string, err error) {
+func detectViaLocaleConf(c *Config) (_ []string, _ string, err error) {
 	defer func() {
 		if err != nil {
 			err = &Error{"detect via locale conf", err}
 		}
 	}()
 
-	fp := getLocaleConfPath()
+	s, fp, err := readLocaleConf(c, CategoryMessages)
+	if err != nil {
+		return nil, "", err
+	}
+	return []string{c.parseEnvLc(s)}, fp, nil
+}
+
+func detectCategoryViaLocaleConf(c *Config, cat Category) (_ string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect category via locale conf", err}
+		}
+	}()
+
+	return readLocaleConf(c, cat)
+}
+
+// SystemLocaleDetector detects locale via distro specific system locale
+// files, see SystemLocalePaths for details.
+var SystemLocaleDetector = newConfigCategoryDetector("system locale", detectViaSystemLocale, detectCategoryViaSystemLocale)
+
+// SystemLocalePaths are the distro specific system locale files which will
+// be read in order after locale.conf:
+//   - "/etc/default/locale" (Debian, Ubuntu)
+//   - "/etc/sysconfig/i18n" (RHEL, CentOS before 7)
+var SystemLocalePaths = []string{
+	"/etc/default/locale",
+	"/etc/sysconfig/i18n",
+}
+
+func detectViaSystemLocale(c *Config) (_ []string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect via system locale", err}
+		}
+	}()
+
+	s, fp, err := readSystemLocale(c, CategoryMessages)
+	if err != nil {
+		return nil, "", err
+	}
+	return []string{c.parseEnvLc(s)}, fp, nil
+}
+
+func detectCategoryViaSystemLocale(c *Config, cat Category) (_ string, _ string, err error) {
+	defer func() {
+		if err != nil {
+			err = &Error{"detect category via system locale", err}
+		}
+	}()
+
+	return readSystemLocale(c, cat)
+}
+
+// readSystemLocale will read the value of category from the first system
+// locale file which sets it, returns the raw value and the path of file.
+//
+// Files not existing or not readable are skipped.
+func readSystemLocale(c *Config, cat Category) (string, string, error) {
+	for _, fp := range SystemLocalePaths {
+		m, err := readEnvFile(c, fp)
+		if err != nil && (errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission)) {
+			continue
+		}
+		if err != nil {
+			return "", "", err
+		}
+
+		_, s, ok := lookupCategory(mapLookup(m), cat)
+		if ok {
+			return s, fp, nil
+		}
+	}
+	return "", "", ErrNotDetected
+}
+
+// readLocaleConf will read the value of category from locale conf, returns
+// the raw value and the path of locale conf.
+func readLocaleConf(c *Config, cat Category) (string, string, error) {
+	fp := getLocaleConfPath(c)
 	if fp == "" {
-		return nil, ErrNotDetected
+		return "", "", ErrNotDetected
 	}
 
-	f, err := os.Open(fp)
+	m, err := readEnvFile(c, fp)
+	if err != nil {
+		return "", "", err
+	}
+
+	_, s, ok := lookupCategory(mapLookup(m), cat)
+	if !ok {
+		return "", "", ErrNotDetected
+	}
+	return s, fp, nil
+}
+
+// readEnvFile will read the KEY=VALUE file fp into a map.
+func readEnvFile(c *Config, fp string) (map[string]string, error) {
+	f, err := c.open(fp)
 	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
+
+	return parseEnvFile(f)
+}
+
+// envFile is a file which sets env, read parses it into a map.
+type envFile struct {
+	path string
+	read func(c *Config, fp string) (map[string]string, error)
+}
 
-	// Output should be like:
-	//
-	// LANG=en_US.UTF-8
-	// LC_CTYPE="en_US.UTF-8"
-	// LC_NUMERIC="en_US.UTF-8"
-	// LC_TIME="en_US.UTF-8"
-	// LC_COLLATE="en_US.UTF-8"
-	// LC_MONETARY="en_US.UTF-8"
-	// LC_MESSAGES=
-	// LC_PAPER="en_US.UTF-8"
-	// LC_NAME="en_US.UTF-8"
-	// LC_ADDRESS="en_US.UTF-8"
-	// LC_TELEPHONE="en_US.UTF-8"
-	// LC_MEASUREMENT="en_US.UTF-8"
-	// LC_IDENTIFICATION="en_US.UTF-8"
-	// LC_ALL=
+// readEnvFiles will read files in order and skip the not existing or not
+// readable ones, returns the merged env and the file path each variable
+// comes from.
+//
+// Later files override variables set by earlier ones.
+func readEnvFiles(c *Config, files []envFile) (map[string]string, map[string]string, error) {
 	m := make(map[string]string)
-	s := bufio.NewScanner(f)
-	for s.Scan() {
-		value := strings.Split(s.Text(), "=")
-		// Ignore not set locale value.
-		if len(value) != 2 || value[1] == "" {
+	sources := make(map[string]string)
+	for _, f := range files {
+		fm, err := f.read(c, f.path)
+		if err != nil && (errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission)) {
 			continue
 		}
-		m[value[0]] = strings.Trim(value[1], "\"")
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, v := range fm {
+			m[k] = v
+			sources[k] = f.path
+		}
 	}
+	return m, sources, nil
+}
 
-	for _, v := range envs {
-		x, ok := m[v]
-		if ok {
-			return []string{parseEnvLc(x)}, nil
-		}
+// detectViaEnvFiles will detect language from the merged env of files,
+// returns the detected languages and the file path they come from.
+func detectViaEnvFiles(c *Config, files []envFile) ([]string, string, error) {
+	m, sources, err := readEnvFiles(c, files)
+	if err != nil {
+		return nil, "", err
+	}
+
+	lang, key, err := detectViaVars(c, m)
+	if err != nil {
+		return nil, "", err
+	}
+	return lang, sources[key], nil
+}
+
+// detectCategoryViaEnvFiles will read the value of category from the merged
+// env of files, returns the raw value and the file path it comes from.
+func detectCategoryViaEnvFiles(c *Config, files []envFile, cat Category) (string, string, error) {
+	m, sources, err := readEnvFiles(c, files)
+	if err != nil {
+		return "", "", err
+	}
+
+	key, s, ok := lookupCategory(mapLookup(m), cat)
+	if !ok {
+		return "", "", ErrNotDetected
 	}
-	return nil, ErrNotDetected
+	return s, sources[key], nil
 }
 
 // getLocaleConfPath will try to get correct locale conf path.
@@ -79,22 +196,22 @@ func detectViaLocaleConf() (_ []string, err error) {
 // ref:
 //   - POSIX Locale: https://pubs.opengroup.org/onlinepubs/9699919799/
 //   - XDG Base Directory: https://specifications.freedesktop.org/basedir-spec/basedir-spec-latest.html
-func getLocaleConfPath() string {
+func getLocaleConfPath(c *Config) string {
 	// Try to loading from $XDG_CONFIG_HOME/locale.conf
-	xdg, ok := os.LookupEnv("XDG_CONFIG_HOME")
+	xdg, ok := c.lookupEnv("XDG_CONFIG_HOME")
 	if ok {
 		fp := path.Join(xdg, "locale.conf")
-		_, err := os.Stat(fp)
+		_, err := c.stat(fp)
 		if err == nil {
 			return fp
 		}
 	}
 
 	// Try to loading from $HOME/.config/locale.conf
-	home, ok := os.LookupEnv("HOME")
+	home, ok := c.lookupEnv("HOME")
 	if ok {
 		fp := path.Join(home, ".config", "locale.conf")
-		_, err := os.Stat(fp)
+		_, err := c.stat(fp)
 		if err == nil {
 			return fp
 		}
@@ -102,7 +219,7 @@ func getLocaleConfPath() string {
 
 	// Try to loading from /etc/locale.conf
 	fp := "/etc/locale.conf"
-	_, err := os.Stat(fp)
+	_, err := c.stat(fp)
 	if err == nil {
 		return fp
 	}
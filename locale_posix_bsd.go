@@ -0,0 +1,18 @@
+//go:build dragonfly || freebsd || netbsd || openbsd
+
+package locale
+
+// BSD login class capabilities are only used on BSD.
+var detectors = []Detector{
+	EnvLanguageDetector,
+	EnvLcDetector,
+	PlasmaDetector,
+	EnvironmentDDetector,
+	PamEnvDetector,
+	LoginConfDetector,
+	LocaleConfDetector,
+	SystemPlasmaDetector,
+	SystemEnvironmentDDetector,
+	SystemPamEnvDetector,
+	SystemLocaleDetector,
+}
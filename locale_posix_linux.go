@@ -0,0 +1,18 @@
+//go:build linux && !android
+
+package locale
+
+// GNOME AccountsService is only used on Linux.
+var detectors = []Detector{
+	EnvLanguageDetector,
+	EnvLcDetector,
+	AccountsServiceDetector,
+	PlasmaDetector,
+	EnvironmentDDetector,
+	PamEnvDetector,
+	LocaleConfDetector,
+	SystemPlasmaDetector,
+	SystemEnvironmentDDetector,
+	SystemPamEnvDetector,
+	SystemLocaleDetector,
+}
@@ -0,0 +1,16 @@
+//go:build aix || hurd || illumos || nacl || plan9 || solaris || zos
+
+package locale
+
+var detectors = []Detector{
+	EnvLanguageDetector,
+	EnvLcDetector,
+	PlasmaDetector,
+	EnvironmentDDetector,
+	PamEnvDetector,
+	LocaleConfDetector,
+	SystemPlasmaDetector,
+	SystemEnvironmentDDetector,
+	SystemPamEnvDetector,
+	SystemLocaleDetector,
+}
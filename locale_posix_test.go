@@ -4,9 +4,15 @@
 package locale
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path"
+	"reflect"
 	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
 )
 
 func TestXDGConfigHome(t *testing.T) {
@@ -19,7 +25,7 @@ func TestXDGConfigHome(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	fp := getLocaleConfPath()
+	fp := getLocaleConfPath(defaultConfig)
 	expected := path.Join(tmpDir, "locale.conf")
 	if fp != expected {
 		t.Errorf("Expected path %s, got %s", expected, fp)
@@ -36,7 +42,7 @@ func TestHOME(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	fp := getLocaleConfPath()
+	fp := getLocaleConfPath(defaultConfig)
 	expected := path.Join(tmpDir, ".config/locale.conf")
 	if fp != expected {
 		t.Errorf("Expected path %s, got %s", expected, fp)
@@ -51,7 +57,7 @@ func TestFallbackToSystem(t *testing.T) {
 		localeExist = true
 	}
 
-	fp := getLocaleConfPath()
+	fp := getLocaleConfPath(defaultConfig)
 	if (fp == "/etc/locale.conf") != localeExist {
 		t.Errorf("Expected path to be /etc/locale.conf: %v, got: %s", localeExist, fp)
 	}
@@ -67,7 +73,7 @@ func TestDetectViaLocaleConf(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	lang, err := detectViaLocaleConf()
+	lang, _, err := detectViaLocaleConf(defaultConfig)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -75,3 +81,315 @@ func TestDetectViaLocaleConf(t *testing.T) {
 		t.Error("Expected non-empty lang, got empty string")
 	}
 }
+
+func TestDetectCategoryViaLocaleConf(t *testing.T) {
+	setupEnv()
+	tmpDir := t.TempDir()
+	err := os.WriteFile(path.Join(tmpDir, "locale.conf"), []byte("LANG=en_US.UTF-8\nLC_TIME=\"de_DE.UTF-8\"\nLC_ALL=\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		category Category
+		want     string
+	}{
+		{CategoryTime, "de_DE.UTF-8"},
+		{CategoryMonetary, "en_US.UTF-8"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.category), func(t *testing.T) {
+			got, src, err := detectCategoryViaLocaleConf(defaultConfig, tt.category)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+			if src != path.Join(tmpDir, "locale.conf") {
+				t.Errorf("Expected source %s, got %s", path.Join(tmpDir, "locale.conf"), src)
+			}
+		})
+	}
+}
+
+func TestConfigLocaleConf(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"HOME": "/home/user"}),
+		FS: fstest.MapFS{
+			"etc/locale.conf":               {Data: []byte("LANG=en_US.UTF-8\n")},
+			"home/user/.config/locale.conf": {Data: []byte("LANG=fr_FR.UTF-8\nLC_TIME=de_DE.UTF-8\n")},
+		},
+		Detectors: []Detector{EnvLanguageDetector, EnvLcDetector, LocaleConfDetector},
+	}
+
+	res, err := c.DetectWithSource()
+	if err != nil {
+		t.Fatalf("DetectWithSource() error = %v", err)
+	}
+	if res.Detector != LocaleConfDetector.Name() || res.Source != "/home/user/.config/locale.conf" {
+		t.Errorf("DetectWithSource() = %+v", res)
+	}
+	if res.Tags[0] != language.MustParse("fr-FR") {
+		t.Errorf("DetectWithSource() tag = %v, want fr-FR", res.Tags[0])
+	}
+
+	tag, err := c.DetectCategory(CategoryTime)
+	if err != nil {
+		t.Fatalf("DetectCategory() error = %v", err)
+	}
+	if tag != language.MustParse("de-DE") {
+		t.Errorf("DetectCategory() = %v, want de-DE", tag)
+	}
+}
+
+func TestReadEnvFilesPermission(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"HOME": "/home/user"}),
+		FS: deniedFS{
+			FS: fstest.MapFS{
+				"etc/security/pam_env.conf":         {Data: []byte("LANG DEFAULT=de_DE.UTF-8\n")},
+				"home/user/.pam_environment":        {Data: []byte("LANG=fr_FR.UTF-8\n")},
+				"etc/xdg/plasma-localerc":           {Data: []byte("[Formats]\nLANG=ja_JP.UTF-8\n")},
+				"home/user/.config/plasma-localerc": {Data: []byte("[Formats]\nLANG=en_GB.UTF-8\n")},
+			},
+			denied: []string{"etc/security/pam_env.conf", "home/user/.config"},
+		},
+	}
+
+	m, sources, err := readEnvFiles(c, []envFile{
+		{"/etc/security/pam_env.conf", readPamEnvFile},
+		{"/home/user/.pam_environment", readPamEnvFile},
+	})
+	if err != nil {
+		t.Fatalf("readEnvFiles() error = %v", err)
+	}
+	if m["LANG"] != "fr_FR.UTF-8" || sources["LANG"] != "/home/user/.pam_environment" {
+		t.Errorf("readEnvFiles() = %v, %v", m, sources)
+	}
+
+	lang, src, err := detectViaPamEnv(c)
+	if err != nil || !reflect.DeepEqual(lang, []string{"fr_FR"}) || src != "/home/user/.pam_environment" {
+		t.Errorf("detectViaPamEnv() = %v, %v, %v", lang, src, err)
+	}
+	_, _, err = detectViaPlasma(c)
+	if !errors.Is(err, ErrNotDetected) {
+		t.Errorf("detectViaPlasma() error = %v, want %v", err, ErrNotDetected)
+	}
+	lang, src, err = detectViaSystemPlasma(c)
+	if err != nil || !reflect.DeepEqual(lang, []string{"ja_JP"}) || src != "/etc/xdg/plasma-localerc" {
+		t.Errorf("detectViaSystemPlasma() = %v, %v, %v", lang, src, err)
+	}
+}
+
+func TestDetectViaSystemLocale(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		files        fs.FS
+		expect       string
+		expectSource string
+		expectErr    error
+	}{
+		{
+			"debian",
+			fstest.MapFS{"etc/default/locale": {Data: []byte("LANG=de_DE.UTF-8\n")}},
+			"de_DE", "/etc/default/locale", nil,
+		},
+		{
+			"rhel",
+			fstest.MapFS{"etc/sysconfig/i18n": {Data: []byte("LANG=\"ja_JP.UTF-8\"\nSYSFONT=\"latarcyrheb-sun16\"\n")}},
+			"ja_JP", "/etc/sysconfig/i18n", nil,
+		},
+		{
+			"debian first",
+			fstest.MapFS{
+				"etc/default/locale": {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"etc/sysconfig/i18n": {Data: []byte("LANG=ja_JP.UTF-8\n")},
+			},
+			"de_DE", "/etc/default/locale", nil,
+		},
+		{
+			"skip file without locale",
+			fstest.MapFS{
+				"etc/default/locale": {Data: []byte("# File generated by update-locale\n")},
+				"etc/sysconfig/i18n": {Data: []byte("LANG=ja_JP.UTF-8\n")},
+			},
+			"ja_JP", "/etc/sysconfig/i18n", nil,
+		},
+		{
+			"skip unreadable file",
+			deniedFS{
+				FS: fstest.MapFS{
+					"etc/default/locale": {Data: []byte("LANG=de_DE.UTF-8\n")},
+					"etc/sysconfig/i18n": {Data: []byte("LANG=ja_JP.UTF-8\n")},
+				},
+				denied: []string{"etc/default/locale"},
+			},
+			"ja_JP", "/etc/sysconfig/i18n", nil,
+		},
+		{"not exist", fstest.MapFS{}, "", "", ErrNotDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{FS: tt.files}
+			lang, src, err := detectViaSystemLocale(c)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("detectViaSystemLocale() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(lang, []string{tt.expect}) {
+				t.Errorf("detectViaSystemLocale() = %v, want %v", lang, tt.expect)
+			}
+			if src != tt.expectSource {
+				t.Errorf("detectViaSystemLocale() source = %v, want %v", src, tt.expectSource)
+			}
+		})
+	}
+}
+
+func TestConfigSystemLocaleCategory(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{}),
+		FS: fstest.MapFS{
+			"etc/default/locale": {Data: []byte("LANG=en_US.UTF-8\nLC_TIME=en_GB.UTF-8\n")},
+		},
+		Detectors: []Detector{EnvLcDetector, LocaleConfDetector, SystemLocaleDetector},
+	}
+
+	tag, err := c.DetectCategory(CategoryTime)
+	if err != nil {
+		t.Fatalf("DetectCategory() error = %v", err)
+	}
+	if tag != language.BritishEnglish {
+		t.Errorf("DetectCategory() = %v, want en-GB", tag)
+	}
+}
+
+// defaultDetectors are the detectors registered by default, captured before
+// tests replace them.
+var defaultDetectors = Detectors()
+
+// TestDefaultDetectorsOrder pins that system level sources never override
+// the user's locale.conf.
+func TestDefaultDetectorsOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		files          fstest.MapFS
+		expect         language.Tag
+		expectDetector string
+	}{
+		{
+			"locale.conf over system plasma",
+			fstest.MapFS{
+				"home/user/.config/locale.conf": {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"etc/xdg/plasma-localerc":       {Data: []byte("[Formats]\nLANG=en_US.UTF-8\n")},
+			},
+			language.MustParse("de-DE"), "locale conf",
+		},
+		{
+			"user plasma over locale.conf",
+			fstest.MapFS{
+				"home/user/.config/locale.conf":     {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"home/user/.config/plasma-localerc": {Data: []byte("[Formats]\nLANG=ja_JP.UTF-8\n")},
+			},
+			language.MustParse("ja-JP"), "plasma",
+		},
+		{
+			"locale.conf over system environment.d",
+			fstest.MapFS{
+				"home/user/.config/locale.conf":             {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"usr/lib/environment.d/99-environment.conf": {Data: []byte("LANG=en_US.UTF-8\n")},
+			},
+			language.MustParse("de-DE"), "locale conf",
+		},
+		{
+			"user environment.d over locale.conf",
+			fstest.MapFS{
+				"home/user/.config/locale.conf":                {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"home/user/.config/environment.d/10-lang.conf": {Data: []byte("LANG=ja_JP.UTF-8\n")},
+			},
+			language.MustParse("ja-JP"), "environment.d",
+		},
+		{
+			"system environment.d over system locale",
+			fstest.MapFS{
+				"etc/environment.d/10-lang.conf": {Data: []byte("LANG=en_US.UTF-8\n")},
+				"etc/default/locale":             {Data: []byte("LANG=fr_FR.UTF-8\n")},
+			},
+			language.MustParse("en-US"), "system environment.d",
+		},
+		{
+			"locale.conf over system pam env",
+			fstest.MapFS{
+				"home/user/.config/locale.conf": {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"etc/security/pam_env.conf":     {Data: []byte("LANG DEFAULT=en_US.UTF-8\n")},
+				"etc/environment":               {Data: []byte("LANG=en_GB.UTF-8\n")},
+			},
+			language.MustParse("de-DE"), "locale conf",
+		},
+		{
+			"user pam env over locale.conf",
+			fstest.MapFS{
+				"home/user/.config/locale.conf": {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"home/user/.pam_environment":    {Data: []byte("LANG DEFAULT=ja_JP.UTF-8\n")},
+			},
+			language.MustParse("ja-JP"), "pam env",
+		},
+		{
+			"system pam env over system locale",
+			fstest.MapFS{
+				"etc/security/pam_env.conf": {Data: []byte("LANG DEFAULT=en_US.UTF-8\n")},
+				"etc/environment":           {Data: []byte("LANG=en_GB.UTF-8\n")},
+				"etc/default/locale":        {Data: []byte("LANG=fr_FR.UTF-8\n")},
+			},
+			language.MustParse("en-GB"), "system pam env",
+		},
+		{
+			"system plasma over system locale",
+			fstest.MapFS{
+				"etc/xdg/plasma-localerc": {Data: []byte("[Formats]\nLANG=en_US.UTF-8\n")},
+				"etc/default/locale":      {Data: []byte("LANG=fr_FR.UTF-8\n")},
+			},
+			language.MustParse("en-US"), "system plasma",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{
+				LookupEnv: mapLookup(map[string]string{"HOME": "/home/user", "USER": "user"}),
+				FS:        tt.files,
+				Detectors: defaultDetectors,
+			}
+			res, err := c.DetectWithSource()
+			if err != nil {
+				t.Fatalf("DetectWithSource() error = %v", err)
+			}
+			if res.Tags[0] != tt.expect || res.Detector != tt.expectDetector {
+				t.Errorf("DetectWithSource() = %v from %v, want %v from %v", res.Tags[0], res.Detector, tt.expect, tt.expectDetector)
+			}
+		})
+	}
+}
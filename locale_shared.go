@@ -1,7 +1,8 @@
 package locale
 
 import (
-	"os"
+	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -16,26 +17,65 @@ import (
 // BenchmarkEnviron-8     	 4275735	       281 ns/op
 // PASS
 
-// envs is the env to be checked.
-//
-// LC_ALL will overwrite all LC_* options.
-// FIXME: LC_ALL=C should overwrite $LANGUAGE env
-//
-// LC_MESSAGES is the config for messages.
-// FIXME: LC_MESSAGES=C should overwrite $LANGUAGE env
-//
-// LANG is the default locale.
-var envs = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+var (
+	// EnvLanguageDetector detects locale via env LANGUAGE.
+	EnvLanguageDetector = newConfigDetector("env language", detectViaEnvLanguage)
+	// EnvLcDetector detects locale via env LC_ALL, LC_MESSAGES and LANG.
+	EnvLcDetector = newConfigCategoryDetector("env lc", detectViaEnvLc, detectCategoryViaEnv)
+)
+
+// detectViaVars will detect language from vars like they are env, returns
+// the detected languages and the variable name they come from.
+func detectViaVars(c *Config, vars map[string]string) ([]string, string, error) {
+	vc := &Config{
+		LookupEnv: mapLookup(vars),
+		FS:        c.FS,
+		Command:   c.Command,
+	}
+	// Call env detectors directly instead of via Config.Detectors, so that
+	// detectors built on it won't depend on the registry.
+	for _, fn := range []func(c *Config) ([]string, string, error){
+		detectViaEnvLanguage,
+		detectViaEnvLc,
+	} {
+		lang, source, err := fn(vc)
+		if err != nil && errors.Is(err, ErrNotDetected) {
+			continue
+		}
+		return lang, source, err
+	}
+	return nil, "", &Error{"detect via vars", ErrNotDetected}
+}
 
 // detectViaEnvLanguage checks env LANGUAGE
 //
-// Program use gettext will respect LANGUAGE env
-func detectViaEnvLanguage() ([]string, error) {
-	s, ok := os.LookupEnv("LANGUAGE")
+// Program use gettext will respect LANGUAGE env, but gettext ignores it
+// while the effective messages locale (LC_ALL > LC_MESSAGES > LANG) is
+// "C" or "POSIX", so that LC_ALL=C LANGUAGE=de yields the C locale.
+//
+// gettext also ignores LANGUAGE while no locale is set at all, we
+// deliberately don't follow this because LANGUAGE is still the best hint of
+// user's intent, and LANGUAGE alone is commonly set in env files.
+//
+// ref:
+//   - https://www.gnu.org/software/gettext/manual/html_node/The-LANGUAGE-variable.html
+func detectViaEnvLanguage(c *Config) ([]string, string, error) {
+	s, ok := c.lookupEnv("LANGUAGE")
 	if !ok || s == "" {
-		return nil, &Error{"detect via env language", ErrNotDetected}
+		return nil, "", &Error{"detect via env language", ErrNotDetected}
+	}
+	if key, lc, ok := lookupCategory(c.lookupEnv, CategoryMessages); ok && isCLocale(lc) {
+		return nil, "", &Error{"detect via env language", &IgnoredError{
+			Value:  s,
+			Source: "LANGUAGE",
+			Reason: fmt.Sprintf("%s is %q", key, lc),
+		}}
 	}
-	return parseEnvLanguage(s), nil
+	langs := c.parseEnvLanguage(s)
+	if len(langs) == 0 {
+		return nil, "", &Error{"detect via env language", ErrNotDetected}
+	}
+	return langs, "LANGUAGE", nil
 }
 
 // detectViaEnvLc checks LC_* in order which decided by
@@ -45,30 +85,90 @@ func detectViaEnvLanguage() ([]string, error) {
 //   - http://man7.org/linux/man-pages/man7/locale.7.html
 //   - https://linux.die.net/man/3/gettext
 //   - https://wiki.archlinux.org/index.php/Locale
-func detectViaEnvLc() ([]string, error) {
-	for _, v := range envs {
-		s, ok := os.LookupEnv(v)
-		if ok && s != "" {
-			return []string{parseEnvLc(s)}, nil
-		}
+func detectViaEnvLc(c *Config) ([]string, string, error) {
+	key, s, ok := lookupCategory(c.lookupEnv, CategoryMessages)
+	if !ok {
+		return nil, "", &Error{"detect via env lc", ErrNotDetected}
 	}
-	return nil, &Error{"detect via env lc", ErrNotDetected}
+	return []string{c.parseEnvLc(s)}, key, nil
+}
+
+// detectCategoryViaEnv checks LC_ALL, LC_<category> and LANG in order.
+func detectCategoryViaEnv(c *Config, cat Category) (string, string, error) {
+	key, s, ok := lookupCategory(c.lookupEnv, cat)
+	if !ok {
+		return "", "", &Error{"detect category via env", ErrNotDetected}
+	}
+	return s, key, nil
 }
 
 // parseEnvLanguage will parse LANGUAGE env.
 // Input could be: "en_AU:en_GB:en"
-func parseEnvLanguage(s string) []string {
-	return strings.Split(s, ":")
+//
+// Empty entries will be ignored like gettext does, others will be parsed
+// as LC_* env.
+func (c *Config) parseEnvLanguage(s string) []string {
+	var langs []string
+	for _, v := range strings.Split(s, ":") {
+		if v != "" {
+			langs = append(langs, c.parseEnvLc(v))
+		}
+	}
+	return langs
+}
+
+// isCLocale checks whether s is the "C" locale.
+//
+// Only "C" and "POSIX" are treated as C locale, "C.UTF-8" is not as what
+// glibc does.
+func isCLocale(s string) bool {
+	return s == "C" || s == "POSIX"
+}
+
+// parseEnvLc is the same as the package level parseEnvLc but will resolve
+// alias like "german" first.
+//
+// Values like "en_US.UTF-8" are not looked up in the alias file, aliases
+// of them only differ in codeset which is dropped anyway.
+func (c *Config) parseEnvLc(s string) string {
+	if !isLocaleName(s) {
+		s = c.resolveAlias(s)
+	}
+	return parseEnvLc(s)
+}
+
+// isLocaleName checks whether s looks like a locale name "ll_CC" with
+// optional codeset and modifier, like "en_US.UTF-8" or "sr_RS@latin".
+func isLocaleName(s string) bool {
+	if i := strings.IndexAny(s, ".@"); i >= 0 {
+		s = s[:i]
+	}
+	lang, region, ok := strings.Cut(s, "_")
+	if !ok || len(lang) < 2 || len(lang) > 3 || len(region) != 2 {
+		return false
+	}
+	for i := 0; i < len(lang); i++ {
+		if lang[i] < 'a' || lang[i] > 'z' {
+			return false
+		}
+	}
+	for i := 0; i < len(region); i++ {
+		if region[i] < 'A' || region[i] > 'Z' {
+			return false
+		}
+	}
+	return true
 }
 
 // parseEnvLc will parse LC_* env.
-// Input could be: "en_US.UTF-8"
+// Input could be: "en_US.UTF-8" or "sr_RS.UTF-8@latin"
+//
+// Codeset will be dropped and well-known modifiers will be converted, so
+// the output could be "en_US" or "sr_Latn_RS".
 func parseEnvLc(s string) string {
-	x := strings.Split(s, ".")
-	// "C" means "ANSI-C" and "POSIX", if locale set to C, we can simple
-	// set returned language to "en_US"
-	if x[0] == "C" {
-		return "en_US"
+	l, err := ParsePOSIX(s)
+	if err != nil {
+		return s
 	}
-	return x[0]
+	return l.identifier()
 }
@@ -16,7 +16,9 @@ func TestDetectViaEnvLanguage(t *testing.T) {
 	}{
 		{"Valid single value", "en_US", []string{"en_US"}, nil},
 		{"Multiple values", "en_US:zh_CN", []string{"en_US", "zh_CN"}, nil},
+		{"Empty entries", ":en_US::zh_CN:", []string{"en_US", "zh_CN"}, nil},
 		{"Empty value", "", nil, ErrNotDetected},
+		{"Only separators", "::", nil, ErrNotDetected},
 	}
 
 	for _, tt := range tests {
@@ -29,14 +31,14 @@ func TestDetectViaEnvLanguage(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			got, err := detectViaEnvLanguage()
+			got, _, err := detectViaEnvLanguage(defaultConfig)
 			t.Logf("langs: %v", got)
 
 			if !errors.Is(err, tt.wantErr) {
-				t.Errorf("detectViaEnvLanguage() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("detectViaEnvLanguage(defaultConfig) error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("detectViaEnvLanguage() = %v, want %v", got, tt.want)
+				t.Errorf("detectViaEnvLanguage(defaultConfig) = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -49,10 +51,12 @@ func TestDetectViaEnvLc(t *testing.T) {
 		envKey  string
 		envVal  string
 		want    []string
+		wantSrc string
 		wantErr error
 	}{
-		{"LC_ALL set", true, "LC_ALL", "en_US.UTF-8", []string{"en_US"}, nil},
-		{"No LC env set", false, "", "", nil, ErrNotDetected},
+		{"LC_ALL set", true, "LC_ALL", "en_US.UTF-8", []string{"en_US"}, "LC_ALL", nil},
+		{"LANG set", true, "LANG", "de_DE.UTF-8", []string{"de_DE"}, "LANG", nil},
+		{"No LC env set", false, "", "", nil, "", ErrNotDetected},
 	}
 
 	for _, tt := range tests {
@@ -67,14 +71,17 @@ func TestDetectViaEnvLc(t *testing.T) {
 				}
 			}
 
-			got, err := detectViaEnvLc()
+			got, src, err := detectViaEnvLc(defaultConfig)
 			t.Logf("langs: %v", got)
 
 			if !errors.Is(err, tt.wantErr) {
-				t.Errorf("detectViaEnvLc() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("detectViaEnvLc(defaultConfig) error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("detectViaEnvLc() = %v, want %v", got, tt.want)
+				t.Errorf("detectViaEnvLc(defaultConfig) = %v, want %v", got, tt.want)
+			}
+			if src != tt.wantSrc {
+				t.Errorf("detectViaEnvLc(defaultConfig) source = %v, want %v", src, tt.wantSrc)
 			}
 		})
 	}
@@ -88,6 +95,9 @@ func TestParseEnvLc(t *testing.T) {
 	}{
 		{"en_US.UTF-8", "en_US.UTF-8", "en_US"},
 		{"C.UTF-8", "C.UTF-8", "en_US"},
+		{"POSIX", "POSIX", "en_US"},
+		{"sr_RS.UTF-8@latin", "sr_RS.UTF-8@latin", "sr_Latn_RS"},
+		{"de_DE@euro", "de_DE@euro", "de_DE_u_cu_eur"},
 	}
 
 	for _, tt := range tests {
@@ -98,3 +108,111 @@ func TestParseEnvLc(t *testing.T) {
 		})
 	}
 }
+
+// TestGettextPrecedence mirrors GNU gettext's documented behaviour about
+// LANGUAGE, LC_ALL, LC_MESSAGES and LANG.
+//
+// ref: https://www.gnu.org/software/gettext/manual/html_node/Locale-Environment-Variables.html
+func TestGettextPrecedence(t *testing.T) {
+	detectors = []Detector{EnvLanguageDetector, EnvLcDetector}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    []string
+		wantSrc string
+	}{
+		{"LANGUAGE over LANG", map[string]string{"LANGUAGE": "de", "LANG": "en_US.UTF-8"}, []string{"de"}, "LANGUAGE"},
+		{"LANGUAGE over LC_ALL", map[string]string{"LANGUAGE": "de:fr", "LC_ALL": "en_US.UTF-8"}, []string{"de", "fr"}, "LANGUAGE"},
+		{"LC_ALL=C ignores LANGUAGE", map[string]string{"LANGUAGE": "de", "LC_ALL": "C"}, []string{"en_US"}, "LC_ALL"},
+		{"LC_ALL=POSIX ignores LANGUAGE", map[string]string{"LANGUAGE": "de", "LC_ALL": "POSIX"}, []string{"en_US"}, "LC_ALL"},
+		{"LC_MESSAGES=C ignores LANGUAGE", map[string]string{"LANGUAGE": "de", "LC_MESSAGES": "C", "LANG": "fr_FR.UTF-8"}, []string{"en_US"}, "LC_MESSAGES"},
+		{"LANG=C ignores LANGUAGE", map[string]string{"LANGUAGE": "de", "LANG": "C"}, []string{"en_US"}, "LANG"},
+		{"LC_ALL overrides LC_MESSAGES=C", map[string]string{"LANGUAGE": "de", "LC_ALL": "fr_FR.UTF-8", "LC_MESSAGES": "C"}, []string{"de"}, "LANGUAGE"},
+		{"LC_MESSAGES overrides LANG=C", map[string]string{"LANGUAGE": "de", "LC_MESSAGES": "fr_FR.UTF-8", "LANG": "C"}, []string{"de"}, "LANGUAGE"},
+		{"Empty LC_ALL is unset", map[string]string{"LANGUAGE": "de", "LC_ALL": "", "LC_MESSAGES": "C"}, []string{"en_US"}, "LC_MESSAGES"},
+		{"LC_ALL=C.UTF-8 keeps LANGUAGE", map[string]string{"LANGUAGE": "de", "LC_ALL": "C.UTF-8"}, []string{"de"}, "LANGUAGE"},
+		{"Other categories don't matter", map[string]string{"LANGUAGE": "de", "LC_TIME": "C", "LANG": "fr_FR.UTF-8"}, []string{"de"}, "LANGUAGE"},
+		{"Empty LANGUAGE", map[string]string{"LANGUAGE": "", "LANG": "ja_JP.UTF-8"}, []string{"ja_JP"}, "LANG"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupEnv()
+			defer setupEnv()
+
+			for k, v := range tt.env {
+				err := os.Setenv(k, v)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			res, err := defaultConfig.detectResult()
+			if err != nil {
+				t.Fatalf("detectResult() error = %v", err)
+			}
+			if !reflect.DeepEqual(res.Raw, tt.want) {
+				t.Errorf("detectResult() = %v, want %v", res.Raw, tt.want)
+			}
+			if res.Source != tt.wantSrc {
+				t.Errorf("detectResult() source = %v, want %v", res.Source, tt.wantSrc)
+			}
+		})
+	}
+}
+
+// TestLanguageWithoutLocale covers the deliberate deviation from gettext:
+// gettext ignores LANGUAGE while no locale is set, but it's still used here.
+func TestLanguageWithoutLocale(t *testing.T) {
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{"LANGUAGE": "de"}),
+		Detectors: []Detector{EnvLanguageDetector, EnvLcDetector},
+	}
+	res, err := c.detectResult()
+	if err != nil {
+		t.Fatalf("detectResult() error = %v", err)
+	}
+	if !reflect.DeepEqual(res.Raw, []string{"de"}) || res.Source != "LANGUAGE" {
+		t.Errorf("detectResult() = %v, %v, want %v, %v", res.Raw, res.Source, []string{"de"}, "LANGUAGE")
+	}
+}
+
+func TestIsLocaleName(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect bool
+	}{
+		{"en_US", true},
+		{"en_US.UTF-8", true},
+		{"sr_RS@latin", true},
+		{"ast_ES.UTF-8", true},
+		{"en", false},
+		{"C", false},
+		{"german", false},
+		{"japanese.euc", false},
+		{"EN_us", false},
+		{"sr_Latn_RS", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := isLocaleName(tt.input); got != tt.expect {
+				t.Errorf("isLocaleName() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func BenchmarkDetectAllEnv(b *testing.B) {
+	c := &Config{
+		LookupEnv: mapLookup(map[string]string{
+			"LANGUAGE": "de_DE.UTF-8:fr_FR:en_GB:en_US",
+			"LANG":     "de_DE.UTF-8",
+		}),
+		Detectors: []Detector{EnvLanguageDetector, EnvLcDetector},
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = c.DetectAll()
+	}
+}
@@ -34,7 +34,7 @@ func (l *mock) set(s []string, e error) {
 }
 
 func TestInternalDetect(t *testing.T) {
-	detectors = []detector{mockLang.get}
+	detectors = []Detector{NewDetector("mock", mockLang.get)}
 
 	testErr := errors.New("test error")
 	tests := []struct {
@@ -53,7 +53,7 @@ func TestInternalDetect(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockLang.set(tt.mockString, tt.mockError)
 
-			lang, err := detect()
+			lang, err := defaultConfig.detect()
 			if !errors.Is(err, tt.expectError) {
 				t.Errorf("detect() error = %v, expectError %v", err, tt.expectError)
 			}
@@ -65,7 +65,7 @@ func TestInternalDetect(t *testing.T) {
 }
 
 func TestDetect(t *testing.T) {
-	detectors = []detector{mockLang.get}
+	detectors = []Detector{NewDetector("mock", mockLang.get)}
 
 	tests := []struct {
 		name        string
@@ -95,7 +95,7 @@ func TestDetect(t *testing.T) {
 }
 
 func BenchmarkDetect(b *testing.B) {
-	detectors = []detector{mockLang.get}
+	detectors = []Detector{NewDetector("mock", mockLang.get)}
 
 	mockLang.set([]string{"en-US"}, nil)
 	for i := 0; i < b.N; i++ {
@@ -104,7 +104,7 @@ func BenchmarkDetect(b *testing.B) {
 }
 
 func TestDetectAll(t *testing.T) {
-	detectors = []detector{mockLang.get}
+	detectors = []Detector{NewDetector("mock", mockLang.get)}
 
 	tests := []struct {
 		name        string
@@ -131,3 +131,118 @@ func TestDetectAll(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectWithSource(t *testing.T) {
+	testErr := errors.New("test error")
+	detectors = []Detector{
+		NewDetector("mock", mockLang.get),
+		NewSourceDetector("source", func() ([]string, string, error) {
+			return []string{"zh_CN", "en"}, "LANGUAGE", nil
+		}),
+	}
+
+	tests := []struct {
+		name        string
+		mockString  []string
+		mockError   error
+		expect      DetectResult
+		expectError error
+	}{
+		{
+			"normal", []string{"en-US"}, nil,
+			DetectResult{
+				Tags:     []language.Tag{language.AmericanEnglish},
+				Detector: "mock",
+				Raw:      []string{"en-US"},
+			},
+			nil,
+		},
+		{
+			"fallback", nil, ErrNotDetected,
+			DetectResult{
+				Tags:     []language.Tag{language.MustParse("zh-Hans-CN"), language.English},
+				Detector: "source",
+				Raw:      []string{"zh_CN", "en"},
+				Source:   "LANGUAGE",
+			},
+			nil,
+		},
+		{"other error", nil, testErr, DetectResult{}, testErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLang.set(tt.mockString, tt.mockError)
+
+			res, err := DetectWithSource()
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("DetectWithSource() error = %v, expectError %v", err, tt.expectError)
+			}
+			if !reflect.DeepEqual(res, tt.expect) {
+				t.Errorf("DetectWithSource() = %v, want %v", res, tt.expect)
+			}
+		})
+	}
+}
+
+func TestDetectStrict(t *testing.T) {
+	testErr := errors.New("test error")
+	detectors = []Detector{
+		NewDetector("mock", mockLang.get),
+		NewSourceDetector("source", func() ([]string, string, error) {
+			return []string{"xx-invalid", "fr"}, "LANGUAGE", nil
+		}),
+	}
+
+	tests := []struct {
+		name        string
+		mockString  []string
+		mockError   error
+		expectLang  language.Tag
+		expectError error
+	}{
+		{"normal", []string{"en-US"}, nil, language.AmericanEnglish, nil},
+		{"next candidate", []string{"ac", "de"}, nil, language.German, nil},
+		{"next detector", []string{"ac", "und"}, nil, language.French, nil},
+		{"not detected", nil, ErrNotDetected, language.French, nil},
+		{"other error", nil, testErr, language.Und, testErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLang.set(tt.mockString, tt.mockError)
+
+			lang, err := DetectStrict()
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("DetectStrict() error = %v, expectError %v", err, tt.expectError)
+			}
+			if lang != tt.expectLang {
+				t.Errorf("DetectStrict() = %v, want %v", lang, tt.expectLang)
+			}
+		})
+	}
+}
+
+func TestDetectStrictInvalid(t *testing.T) {
+	detectors = []Detector{
+		NewSourceDetector("source", func() ([]string, string, error) {
+			return []string{"ac"}, "LANG", nil
+		}),
+	}
+
+	lang, err := DetectStrict()
+	if lang != language.Und {
+		t.Errorf("DetectStrict() = %v, want %v", lang, language.Und)
+	}
+	if !errors.Is(err, ErrInvalidLocale) {
+		t.Fatalf("DetectStrict() error = %v, expectError %v", err, ErrInvalidLocale)
+	}
+
+	var ie *InvalidLocaleError
+	if !errors.As(err, &ie) {
+		t.Fatalf("DetectStrict() error = %v, expect *InvalidLocaleError", err)
+	}
+	if ie.Value != "ac" || ie.Source != "LANG" || ie.Detector != "source" {
+		t.Errorf("DetectStrict() error = %+v", ie)
+	}
+}
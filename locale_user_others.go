@@ -0,0 +1,18 @@
+//go:build !(aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) || android
+
+package locale
+
+import (
+	"golang.org/x/text/language"
+)
+
+// DetectForUser will detect the language of user account username, which
+// is only supported on POSIX compatible systems.
+func DetectForUser(username string) (tag language.Tag, err error) {
+	return defaultConfig.DetectForUser(username)
+}
+
+// DetectForUser will detect the language of user account username with c.
+func (c *Config) DetectForUser(username string) (tag language.Tag, err error) {
+	return language.Und, &Error{"detect for user", ErrNotSupported}
+}
@@ -0,0 +1,83 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"errors"
+	"io/fs"
+	"os/user"
+	"path"
+
+	"golang.org/x/text/language"
+)
+
+// DetectForUser will detect the language of user account username.
+//
+// It's useful for system services which are not running as the user.
+// Following files will be read in the same order as Detect, files not
+// existing or not readable (like in a home directory with mode 0700) are
+// skipped:
+//   - "~/.config/plasma-localerc"
+//   - "~/.config/environment.d/*.conf"
+//   - "~/.pam_environment"
+//   - "~/.config/locale.conf"
+//   - "/etc/locale.conf" (system level locale config)
+//   - SystemLocalePaths (distro specific system locale files)
+func DetectForUser(username string) (tag language.Tag, err error) {
+	return defaultConfig.DetectForUser(username)
+}
+
+// DetectForUser will detect the language of user account username with c.
+func (c *Config) DetectForUser(username string) (tag language.Tag, err error) {
+	u, err := user.Lookup(username)
+	if err != nil {
+		return language.Und, &Error{"detect for user", err}
+	}
+	return c.detectForHome(u.HomeDir)
+}
+
+// detectForHome will detect the language of the user whose home directory
+// is home.
+func (c *Config) detectForHome(home string) (language.Tag, error) {
+	files := []envFile{
+		{path.Join(home, ".config", "plasma-localerc"), readPlasmaLocalerc},
+		{path.Join(home, ".config", "environment.d"), readUserEnvironmentD},
+		{path.Join(home, ".pam_environment"), readPamEnvFile},
+		{path.Join(home, ".config", "locale.conf"), readEnvFile},
+		{"/etc/locale.conf", readEnvFile},
+	}
+	for _, fp := range SystemLocalePaths {
+		files = append(files, envFile{fp, readEnvFile})
+	}
+
+	for _, f := range files {
+		m, err := f.read(c, f.path)
+		if err != nil && (errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission)) {
+			continue
+		}
+		if err != nil {
+			return language.Und, &Error{"detect for user", err}
+		}
+
+		lang, _, err := detectViaVars(c, m)
+		if err != nil && errors.Is(err, ErrNotDetected) {
+			continue
+		}
+		if err != nil {
+			return language.Und, err
+		}
+		return makeTag(lang[0]), nil
+	}
+	return language.Und, &Error{"detect for user", ErrNotDetected}
+}
+
+// readUserEnvironmentD will read "*.conf" files in the user's environment.d
+// dir, see getEnvironmentDFiles for details.
+func readUserEnvironmentD(c *Config, dir string) (map[string]string, error) {
+	files, err := getEnvironmentDFiles(c, []string{dir})
+	if err != nil {
+		return nil, err
+	}
+	m, _, err := readEnvFiles(c, files)
+	return m, err
+}
@@ -0,0 +1,111 @@
+//go:build (aix || dragonfly || freebsd || hurd || illumos || linux || nacl || netbsd || openbsd || plan9 || solaris || zos) && !android
+
+package locale
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+func TestDetectForHome(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		files       fs.FS
+		expectLang  language.Tag
+		expectError error
+	}{
+		{
+			"locale conf",
+			fstest.MapFS{
+				"home/user/.config/locale.conf": {Data: []byte("LANG=de_DE.UTF-8\n")},
+				"etc/locale.conf":               {Data: []byte("LANG=en_US.UTF-8\n")},
+			},
+			language.MustParse("de-DE"), nil,
+		},
+		{
+			"same order as detect",
+			fstest.MapFS{
+				"home/user/.config/plasma-localerc":            {Data: []byte("[Formats]\nLANG=ko_KR.UTF-8\n")},
+				"home/user/.config/environment.d/10-lang.conf": {Data: []byte("LANG=ja_JP.UTF-8\n")},
+				"home/user/.pam_environment":                   {Data: []byte("LANG DEFAULT=fr_FR.UTF-8\n")},
+				"home/user/.config/locale.conf":                {Data: []byte("LANG=de_DE.UTF-8\n")},
+			},
+			language.MustParse("ko-KR"), nil,
+		},
+		{
+			"pam environment over locale conf",
+			fstest.MapFS{
+				"home/user/.pam_environment":    {Data: []byte("LANG DEFAULT=fr_FR.UTF-8\n")},
+				"home/user/.config/locale.conf": {Data: []byte("LANG=de_DE.UTF-8\n")},
+			},
+			language.MustParse("fr-FR"), nil,
+		},
+		{
+			"pam environment",
+			fstest.MapFS{
+				"home/user/.pam_environment": {Data: []byte("LANGUAGE\tDEFAULT=fr_FR:fr\nLANG DEFAULT=en_US.UTF-8\n")},
+				"etc/locale.conf":            {Data: []byte("LANG=en_US.UTF-8\n")},
+			},
+			language.MustParse("fr-FR"), nil,
+		},
+		{
+			"environment.d in lexical order",
+			fstest.MapFS{
+				"home/user/.config/environment.d/10-lang.conf": {Data: []byte("LANG=fr_FR.UTF-8\n")},
+				"home/user/.config/environment.d/20-lang.conf": {Data: []byte("LANG=ja_JP.UTF-8\n")},
+				"home/user/.config/environment.d/30-lang.txt":  {Data: []byte("LANG=ko_KR.UTF-8\n")},
+				"etc/locale.conf": {Data: []byte("LANG=en_US.UTF-8\n")},
+			},
+			language.MustParse("ja-JP"), nil,
+		},
+		{
+			"system fallback",
+			fstest.MapFS{
+				"home/user/.pam_environment": {Data: []byte("PATH DEFAULT=/usr/bin\n")},
+				"etc/locale.conf":            {Data: []byte("LANG=en_US.UTF-8\n")},
+			},
+			language.AmericanEnglish, nil,
+		},
+		{
+			"skip unreadable home",
+			deniedFS{
+				FS: fstest.MapFS{
+					"home/user/.config/locale.conf": {Data: []byte("LANG=de_DE.UTF-8\n")},
+					"home/user/.pam_environment":    {Data: []byte("LANG DEFAULT=fr_FR.UTF-8\n")},
+					"etc/default/locale":            {Data: []byte("LANG=en_GB.UTF-8\n")},
+				},
+				denied: []string{"home/user"},
+			},
+			language.BritishEnglish, nil,
+		},
+		{"nothing", fstest.MapFS{}, language.Und, ErrNotDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{FS: tt.files}
+			lang, err := c.detectForHome("/home/user")
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("detectForHome() error = %v, expectError %v", err, tt.expectError)
+			}
+			if lang != tt.expectLang {
+				t.Errorf("detectForHome() = %v, want %v", lang, tt.expectLang)
+			}
+		})
+	}
+}
+
+func TestDetectForUnknownUser(t *testing.T) {
+	_, err := DetectForUser("go-locale-user-not-exist")
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
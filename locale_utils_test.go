@@ -1,6 +1,7 @@
 package locale
 
 import (
+	"io/fs"
 	"io/ioutil"
 	"os"
 	"path"
@@ -17,6 +18,28 @@ var env struct {
 	sync.Once
 }
 
+// permissionFS is a fs.FS that denies all access.
+type permissionFS struct{}
+
+func (permissionFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+}
+
+// deniedFS is a fs.FS that denies access to paths under denied.
+type deniedFS struct {
+	fs.FS
+	denied []string
+}
+
+func (d deniedFS) Open(name string) (fs.File, error) {
+	for _, v := range d.denied {
+		if name == v || strings.HasPrefix(name, v+"/") {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+		}
+	}
+	return d.FS.Open(name)
+}
+
 func setupLocaleConf(filePath string) (dir string) {
 	confContent := `LANG=en_US.UTF-8`
 	tmpDir := "/tmp/" + time.Now().String()
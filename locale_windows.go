@@ -4,16 +4,21 @@ import (
 	"golang.org/x/sys/windows/registry"
 )
 
-var detectors = []detector{
-	detectViaEnvLanguage,
-	detectViaEnvLc,
-	detectViaRegistry,
+var detectors = []Detector{
+	EnvLanguageDetector,
+	EnvLcDetector,
+	RegistryDetector,
 }
 
+// RegistryDetector detects locale via Windows Registry.
+var RegistryDetector = newConfigDetector("registry", detectViaRegistry)
+
 // detectViaRegistry will detect language via Windows Registry
 //
+// Registry is not affected by Config.
+//
 // ref: https://renenyffenegger.ch/notes/Windows/registry/tree/HKEY_CURRENT_USER/Control-Panel/International/index
-func detectViaRegistry() (langs []string, err error) {
+func detectViaRegistry(_ *Config) (langs []string, source string, err error) {
 	defer func() {
 		if err != nil {
 			err = &Error{"detect via registry", err}
@@ -22,14 +27,14 @@ func detectViaRegistry() (langs []string, err error) {
 
 	key, err := registry.OpenKey(registry.CURRENT_USER, `Control Panel\International`, registry.QUERY_VALUE)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer key.Close()
 
 	lang, _, err := key.GetStringValue("LocaleName")
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return []string{lang}, nil
+	return []string{lang}, `HKEY_CURRENT_USER\Control Panel\International\LocaleName`, nil
 }
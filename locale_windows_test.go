@@ -5,7 +5,7 @@ import (
 )
 
 func Test_detectViaRegistry(t *testing.T) {
-	langs, err := detectViaRegistry()
+	langs, _, err := detectViaRegistry(defaultConfig)
 
 	t.Logf("langs: %v", langs)
 
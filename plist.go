@@ -0,0 +1,429 @@
+package locale
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// plistEpoch is the reference date of binary plist dates.
+var plistEpoch = time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// maxPlistDepth is the max nesting depth of containers in plist.
+const maxPlistDepth = 128
+
+// maxPlistObjects is the max number of objects decoded from a binary plist,
+// objects could be referenced many times so that it's not bounded by size.
+const maxPlistObjects = 1 << 20
+
+// decodePlist will decode a binary ("bplist00") or XML property list.
+//
+// Values are decoded into:
+//   - dict: map[string]any
+//   - array and set: []any
+//   - string: string
+//   - integer: int64 (uint64 if it doesn't fit)
+//   - real: float64
+//   - boolean: bool
+//   - date: time.Time
+//   - data: []byte
+//
+// ref:
+//   - https://opensource.apple.com/source/CF/CF-1153.18/CFBinaryPList.c
+//   - https://www.apple.com/DTDs/PropertyList-1.0.dtd
+func decodePlist(data []byte) (any, error) {
+	if bytes.HasPrefix(data, []byte("bplist00")) {
+		return decodeBinaryPlist(data)
+	}
+	return decodeXMLPlist(data)
+}
+
+// binaryPlist is the decoding state of a binary plist.
+type binaryPlist struct {
+	data    []byte
+	refSize int
+	offsets []uint64
+	// visiting marks the objects being decoded to detect cycles.
+	visiting []bool
+	// decoded is the number of objects decoded.
+	decoded int
+}
+
+func decodeBinaryPlist(data []byte) (any, error) {
+	// Header is 8 bytes and trailer is 32 bytes.
+	if len(data) < 8+32 {
+		return nil, errors.New("binary plist is too short")
+	}
+
+	trailer := data[len(data)-32:]
+	offsetSize := int(trailer[6])
+	refSize := int(trailer[7])
+	numObjects := binary.BigEndian.Uint64(trailer[8:16])
+	topObject := binary.BigEndian.Uint64(trailer[16:24])
+	tableOffset := binary.BigEndian.Uint64(trailer[24:32])
+
+	if offsetSize < 1 || offsetSize > 8 || refSize < 1 || refSize > 8 {
+		return nil, errors.New("binary plist has invalid trailer")
+	}
+	end := uint64(len(data) - 32)
+	if tableOffset < 8 || tableOffset >= end || numObjects == 0 ||
+		numObjects > (end-tableOffset)/uint64(offsetSize) || topObject >= numObjects {
+		return nil, errors.New("binary plist has invalid offset table")
+	}
+
+	p := &binaryPlist{
+		data:     data[:tableOffset],
+		refSize:  refSize,
+		offsets:  make([]uint64, numObjects),
+		visiting: make([]bool, numObjects),
+	}
+	table := data[tableOffset:]
+	for i := range p.offsets {
+		p.offsets[i] = readUint(table[i*offsetSize : (i+1)*offsetSize])
+	}
+	return p.object(topObject, 0)
+}
+
+// readUint reads big endian unsigned integer b which has at most 8 bytes.
+func readUint(b []byte) uint64 {
+	var n uint64
+	for _, v := range b {
+		n = n<<8 | uint64(v)
+	}
+	return n
+}
+
+// slice returns n bytes at offset off.
+func (p *binaryPlist) slice(off, n uint64) ([]byte, error) {
+	if off > uint64(len(p.data)) || n > uint64(len(p.data))-off {
+		return nil, errors.New("binary plist object is out of range")
+	}
+	return p.data[off : off+n], nil
+}
+
+// object decodes the object ref.
+func (p *binaryPlist) object(ref uint64, depth int) (v any, err error) {
+	if ref >= uint64(len(p.offsets)) {
+		return nil, fmt.Errorf("binary plist object ref %d is out of range", ref)
+	}
+	if depth > maxPlistDepth || p.visiting[ref] {
+		return nil, errors.New("binary plist has too deep or cyclic objects")
+	}
+	if p.decoded++; p.decoded > maxPlistObjects {
+		return nil, errors.New("binary plist has too many objects")
+	}
+	p.visiting[ref] = true
+	defer func() { p.visiting[ref] = false }()
+
+	off := p.offsets[ref]
+	b, err := p.slice(off, 1)
+	if err != nil {
+		return nil, err
+	}
+	marker, info := b[0]>>4, b[0]&0x0f
+	off++
+
+	switch marker {
+	case 0x0:
+		switch info {
+		case 0x8:
+			return false, nil
+		case 0x9:
+			return true, nil
+		}
+		return nil, fmt.Errorf("binary plist has unsupported marker %#x", b[0])
+	case 0x1:
+		return p.integer(off, info)
+	case 0x2:
+		return p.real(off, info)
+	case 0x3:
+		if info != 0x3 {
+			return nil, fmt.Errorf("binary plist has unsupported marker %#x", b[0])
+		}
+		f, err := p.real(off, 3)
+		if err != nil {
+			return nil, err
+		}
+		sec, frac := math.Modf(f.(float64))
+		return plistEpoch.Add(time.Duration(sec)*time.Second + time.Duration(frac*float64(time.Second))), nil
+	case 0x4, 0x5, 0x6:
+		n, off, err := p.count(off, info)
+		if err != nil {
+			return nil, err
+		}
+		switch marker {
+		case 0x4:
+			b, err := p.slice(off, n)
+			if err != nil {
+				return nil, err
+			}
+			return append([]byte(nil), b...), nil
+		case 0x5:
+			b, err := p.slice(off, n)
+			if err != nil {
+				return nil, err
+			}
+			return string(b), nil
+		}
+		if n > math.MaxUint64/2 {
+			return nil, errors.New("binary plist object is out of range")
+		}
+		b, err := p.slice(off, n*2)
+		if err != nil {
+			return nil, err
+		}
+		u := make([]uint16, n)
+		for i := range u {
+			u[i] = binary.BigEndian.Uint16(b[i*2:])
+		}
+		return string(utf16.Decode(u)), nil
+	case 0x8:
+		// UID is only used by NSKeyedArchiver, decode it as integer.
+		b, err := p.slice(off, uint64(info)+1)
+		if err != nil {
+			return nil, err
+		}
+		return int64(readUint(b)), nil
+	case 0xa, 0xc:
+		refs, err := p.refs(off, info, 1)
+		if err != nil {
+			return nil, err
+		}
+		arr := make([]any, len(refs))
+		for i, r := range refs {
+			if arr[i], err = p.object(r, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	case 0xd:
+		refs, err := p.refs(off, info, 2)
+		if err != nil {
+			return nil, err
+		}
+		n := len(refs) / 2
+		m := make(map[string]any, n)
+		for i := 0; i < n; i++ {
+			k, err := p.object(refs[i], depth+1)
+			if err != nil {
+				return nil, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, errors.New("binary plist has non-string dict key")
+			}
+			if m[key], err = p.object(refs[n+i], depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("binary plist has unsupported marker %#x", b[0])
+}
+
+// integer decodes an integer of 2^info bytes at off.
+func (p *binaryPlist) integer(off uint64, info byte) (any, error) {
+	if info > 4 {
+		return nil, errors.New("binary plist has invalid integer size")
+	}
+	b, err := p.slice(off, 1<<info)
+	if err != nil {
+		return nil, err
+	}
+	switch len(b) {
+	case 8:
+		return int64(readUint(b)), nil
+	case 16:
+		// 128-bit integers are only used for unsigned 64-bit values.
+		n := readUint(b[8:])
+		if n > math.MaxInt64 {
+			return n, nil
+		}
+		return int64(n), nil
+	}
+	// 1, 2 and 4 bytes integers are unsigned.
+	return int64(readUint(b)), nil
+}
+
+// real decodes a real of 2^info bytes at off.
+func (p *binaryPlist) real(off uint64, info byte) (any, error) {
+	switch info {
+	case 2:
+		b, err := p.slice(off, 4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 3:
+		b, err := p.slice(off, 8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	}
+	return nil, errors.New("binary plist has invalid real size")
+}
+
+// count decodes the count of object at off, returns the count and the
+// offset of object content.
+//
+// Count larger than 14 is stored in the following integer object.
+func (p *binaryPlist) count(off uint64, info byte) (uint64, uint64, error) {
+	if info != 0xf {
+		return uint64(info), off, nil
+	}
+
+	b, err := p.slice(off, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	if b[0]>>4 != 0x1 || b[0]&0x0f > 3 {
+		return 0, 0, errors.New("binary plist has invalid count")
+	}
+	size := uint64(1) << (b[0] & 0x0f)
+	b, err = p.slice(off+1, size)
+	if err != nil {
+		return 0, 0, err
+	}
+	return readUint(b), off + 1 + size, nil
+}
+
+// refs decodes count*per object refs at off.
+func (p *binaryPlist) refs(off uint64, info byte, per uint64) ([]uint64, error) {
+	n, off, err := p.count(off, info)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(p.data))/per {
+		return nil, errors.New("binary plist object is out of range")
+	}
+	b, err := p.slice(off, n*per*uint64(p.refSize))
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]uint64, n*per)
+	for i := range refs {
+		refs[i] = readUint(b[i*p.refSize : (i+1)*p.refSize])
+	}
+	return refs, nil
+}
+
+func decodeXMLPlist(data []byte) (any, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	// Property lists declare a DTD which could be ignored.
+	d.Strict = false
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("xml plist has no value")
+			}
+			return nil, err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if se.Name.Local == "plist" {
+			continue
+		}
+		return decodeXMLValue(d, se, 0)
+	}
+}
+
+// decodeXMLValue decodes the value of element se.
+func decodeXMLValue(d *xml.Decoder, se xml.StartElement, depth int) (any, error) {
+	if depth > maxPlistDepth {
+		return nil, errors.New("xml plist is too deep")
+	}
+
+	switch se.Name.Local {
+	case "dict":
+		m := make(map[string]any)
+		var key *string
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.EndElement:
+				return m, nil
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					var k string
+					if err := d.DecodeElement(&k, &t); err != nil {
+						return nil, err
+					}
+					key = &k
+					continue
+				}
+				if key == nil {
+					return nil, errors.New("xml plist has dict value without key")
+				}
+				v, err := decodeXMLValue(d, t, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				m[*key] = v
+				key = nil
+			}
+		}
+	case "array":
+		arr := make([]any, 0)
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.EndElement:
+				return arr, nil
+			case xml.StartElement:
+				v, err := decodeXMLValue(d, t, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+		}
+	case "true", "false":
+		if err := d.Skip(); err != nil {
+			return nil, err
+		}
+		return se.Name.Local == "true", nil
+	}
+
+	var s string
+	if err := d.DecodeElement(&s, &se); err != nil {
+		return nil, err
+	}
+	switch se.Name.Local {
+	case "string":
+		return s, nil
+	case "integer":
+		s = strings.TrimSpace(s)
+		if n, err := strconv.ParseInt(s, 0, 64); err == nil {
+			return n, nil
+		}
+		return strconv.ParseUint(s, 0, 64)
+	case "real":
+		return strconv.ParseFloat(strings.TrimSpace(s), 64)
+	case "date":
+		return time.Parse(time.RFC3339, strings.TrimSpace(s))
+	case "data":
+		return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+	}
+	return nil, fmt.Errorf("xml plist has unsupported element %q", se.Name.Local)
+}
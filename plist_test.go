@@ -0,0 +1,103 @@
+package locale
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// readPlistFixture reads plist fixture name in testdata/plist.
+func readPlistFixture(t testing.TB, name string) []byte {
+	data, err := os.ReadFile("testdata/plist/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDecodePlist(t *testing.T) {
+	expect := map[string]any{
+		"AppleLocale":                   "en_US@rg=gbzzzz",
+		"AppleLanguages":                []any{"en-US", "zh-Hans-CN", "ja-JP"},
+		"AppleMeasurementUnits":         "Centimeters",
+		"AppleMetricUnits":              true,
+		"AppleTemperatureUnit":          "Celsius",
+		"AppleFirstWeekday":             map[string]any{"gregorian": int64(2)},
+		"AppleICUForce24HourTime":       true,
+		"AppleICUDateFormatStrings":     map[string]any{"1": "y-MM-dd"},
+		"AppleKeyboardUIMode":           int64(2),
+		"AppleMiniaturizeOnDoubleClick": false,
+		"NSUserKeyEquivalents":          map[string]any{},
+		"com.apple.sound.beep.volume":   0.5,
+		"Negative":                      int64(-42),
+		"Large":                         int64(1 << 40),
+		"Date":                          time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+		"Data":                          []byte("\x00\x01go-locale"),
+		"Unicode":                       "简体中文 – Français",
+	}
+
+	for _, name := range []string{"GlobalPreferences.plist", "GlobalPreferences.xml.plist"} {
+		t.Run(name, func(t *testing.T) {
+			got, err := decodePlist(readPlistFixture(t, name))
+			if err != nil {
+				t.Fatalf("decodePlist() error = %v", err)
+			}
+			m, ok := got.(map[string]any)
+			if !ok {
+				t.Fatalf("decodePlist() = %T, want map", got)
+			}
+			// time.Time could not be compared via DeepEqual.
+			if d, ok := m["Date"].(time.Time); !ok || !d.Equal(expect["Date"].(time.Time)) {
+				t.Errorf("decodePlist() Date = %v, want %v", m["Date"], expect["Date"])
+			}
+			m["Date"] = expect["Date"]
+			if !reflect.DeepEqual(m, expect) {
+				t.Errorf("decodePlist() = %#v, want %#v", m, expect)
+			}
+		})
+	}
+}
+
+func TestDecodePlistInvalid(t *testing.T) {
+	valid := readPlistFixture(t, "GlobalPreferences.plist")
+
+	// Object 0 refers to itself.
+	cyclic := append([]byte("bplist00"), 0xa1, 0x00)
+	cyclic = append(cyclic, 0x08)
+	cyclic = append(cyclic, 0, 0, 0, 0, 0, 0, 1, 1,
+		0, 0, 0, 0, 0, 0, 0, 1,
+		0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 10)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"short binary", []byte("bplist00")},
+		{"truncated binary", valid[:len(valid)-1]},
+		{"cyclic binary", cyclic},
+		{"invalid xml", []byte("<plist><dict><key>a</key>")},
+		{"invalid integer", []byte("<plist><integer>x</integer></plist>")},
+		{"unknown element", []byte("<plist><foo/></plist>")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodePlist(tt.data)
+			if err == nil {
+				t.Error("decodePlist() expects error")
+			}
+		})
+	}
+}
+
+func FuzzDecodePlist(f *testing.F) {
+	for _, name := range []string{"GlobalPreferences.plist", "GlobalPreferences.xml.plist"} {
+		f.Add(readPlistFixture(f, name))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = decodePlist(data)
+	})
+}
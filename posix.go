@@ -0,0 +1,90 @@
+package locale
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// POSIXLocale is a parsed POSIX locale name.
+type POSIXLocale struct {
+	// Language is the language part, like "sr".
+	Language string
+	// Territory is the territory part, like "RS".
+	Territory string
+	// Codeset is the character encoding part, like "UTF-8".
+	Codeset string
+	// Modifier is the modifier part without "@", like "latin".
+	Modifier string
+}
+
+// posixModifiers maps well-known POSIX modifiers into BCP 47 subtags.
+//
+// ref: https://www.gnu.org/software/libc/manual/html_node/Locale-Names.html
+var posixModifiers = map[string]struct {
+	script    string
+	variant   string
+	extension string
+}{
+	"latin":      {script: "Latn"},
+	"cyrillic":   {script: "Cyrl"},
+	"devanagari": {script: "Deva"},
+	"valencia":   {variant: "valencia"},
+	// euro means the currency is Euro instead of the legacy one.
+	"euro": {extension: "u_cu_eur"},
+}
+
+// ParsePOSIX will parse a POSIX locale name.
+//
+// Input should be like: "language[_territory][.codeset][@modifier]",
+// for example "sr_RS.UTF-8@latin".
+func ParsePOSIX(s string) (l POSIXLocale, err error) {
+	if i := strings.LastIndexByte(s, '@'); i >= 0 {
+		s, l.Modifier = s[:i], s[i+1:]
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s, l.Codeset = s[:i], s[i+1:]
+	}
+	if i := strings.IndexByte(s, '_'); i >= 0 {
+		s, l.Territory = s[:i], s[i+1:]
+	}
+	l.Language = s
+
+	if l.Language == "" {
+		return POSIXLocale{}, &Error{"parse posix", errors.New("language is empty")}
+	}
+	return l, nil
+}
+
+// Tag will convert l into language.Tag, well-known modifiers will be
+// converted into BCP 47 script, variant or extension subtags.
+func (l POSIXLocale) Tag() (language.Tag, error) {
+	return language.Parse(l.identifier())
+}
+
+// identifier returns an underscore separated identifier that could be
+// parsed by language.Make, like "sr_Latn_RS".
+func (l POSIXLocale) identifier() string {
+	// "C" means "ANSI-C" and "POSIX", if locale set to C, we can simple
+	// set returned language to "en_US"
+	if isCLocale(l.Language) {
+		return "en_US"
+	}
+
+	parts := []string{l.Language}
+	m := posixModifiers[strings.ToLower(l.Modifier)]
+	if m.script != "" {
+		parts = append(parts, m.script)
+	}
+	if l.Territory != "" {
+		parts = append(parts, l.Territory)
+	}
+	if m.variant != "" {
+		parts = append(parts, m.variant)
+	}
+	if m.extension != "" {
+		parts = append(parts, m.extension)
+	}
+	return strings.Join(parts, "_")
+}
@@ -0,0 +1,77 @@
+package locale
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestParsePOSIX(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    POSIXLocale
+		wantTag language.Tag
+		wantErr bool
+	}{
+		{"language only", "de", POSIXLocale{Language: "de"}, language.German, false},
+		{"territory", "en_US", POSIXLocale{Language: "en", Territory: "US"}, language.AmericanEnglish, false},
+		{"codeset", "en_US.UTF-8", POSIXLocale{Language: "en", Territory: "US", Codeset: "UTF-8"}, language.AmericanEnglish, false},
+		{
+			"latin", "sr_RS.UTF-8@latin",
+			POSIXLocale{Language: "sr", Territory: "RS", Codeset: "UTF-8", Modifier: "latin"},
+			language.MustParse("sr-Latn-RS"), false,
+		},
+		{
+			"cyrillic", "uz_UZ@cyrillic",
+			POSIXLocale{Language: "uz", Territory: "UZ", Modifier: "cyrillic"},
+			language.MustParse("uz-Cyrl-UZ"), false,
+		},
+		{
+			"devanagari", "sd_IN@devanagari",
+			POSIXLocale{Language: "sd", Territory: "IN", Modifier: "devanagari"},
+			language.MustParse("sd-Deva-IN"), false,
+		},
+		{
+			"valencia", "ca_ES.UTF-8@valencia",
+			POSIXLocale{Language: "ca", Territory: "ES", Codeset: "UTF-8", Modifier: "valencia"},
+			language.MustParse("ca-ES-valencia"), false,
+		},
+		{
+			"euro", "de_DE@euro",
+			POSIXLocale{Language: "de", Territory: "DE", Modifier: "euro"},
+			language.MustParse("de-DE-u-cu-eur"), false,
+		},
+		{
+			"unknown modifier", "en_US.UTF-8@quot",
+			POSIXLocale{Language: "en", Territory: "US", Codeset: "UTF-8", Modifier: "quot"},
+			language.AmericanEnglish, false,
+		},
+		{"C", "C.UTF-8", POSIXLocale{Language: "C", Codeset: "UTF-8"}, language.AmericanEnglish, false},
+		{"empty", "", POSIXLocale{}, language.Und, true},
+		{"no language", "_US.UTF-8", POSIXLocale{}, language.Und, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePOSIX(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePOSIX() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePOSIX() = %+v, want %+v", got, tt.want)
+			}
+			if err != nil {
+				return
+			}
+
+			tag, err := got.Tag()
+			if err != nil {
+				t.Errorf("Tag() error = %v", err)
+			}
+			if tag != tt.wantTag {
+				t.Errorf("Tag() = %v, want %v", tag, tt.wantTag)
+			}
+		})
+	}
+}